@@ -0,0 +1,60 @@
+package assets
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ErrorKind int
+
+const (
+	// Unknown means the name isn't in the manifest.
+	Unknown ErrorKind = iota
+	// Missing means the manifest names a file that isn't there.
+	Missing
+	// Corrupt means the file is there but doesn't decode.
+	Corrupt
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case Unknown:
+		return "unknown asset"
+	case Missing:
+		return "missing file"
+	case Corrupt:
+		return "corrupt file"
+	}
+	return "invalid"
+}
+
+// Error describes why one asset couldn't be loaded.
+type Error struct {
+	Kind ErrorKind
+	Name string
+	Path string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Kind == Unknown {
+		return fmt.Sprintf("%s %q", e.Kind, e.Name)
+	}
+	return fmt.Sprintf("%s %q (%s): %v", e.Kind, e.Name, e.Path, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errors collects every failure from a Preload, so one run reports all broken
+// assets rather than just the first.
+type Errors []*Error
+
+func (errs Errors) Error() string {
+	msgs := []string{}
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
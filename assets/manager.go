@@ -0,0 +1,168 @@
+// Package assets loads the game's pictures once and hands out shared
+// references to them by name.
+package assets
+
+import (
+	"encoding/json"
+	"errors"
+	"image"
+	_ "image/png"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/TheKaterTot/pixelTest/collide"
+	"github.com/faiface/pixel"
+)
+
+// Manifest maps asset names to slash-separated paths in the asset filesystem.
+type Manifest map[string]string
+
+type Manager struct {
+	fsys     fs.FS
+	manifest Manifest
+	pictures map[string]pixel.Picture
+	sprites  map[string]*pixel.Sprite
+	hitboxes map[string]*collide.Hitbox
+}
+
+func NewManager(fsys fs.FS, manifest Manifest) *Manager {
+	return &Manager{
+		fsys:     fsys,
+		manifest: manifest,
+		pictures: map[string]pixel.Picture{},
+		sprites:  map[string]*pixel.Sprite{},
+		hitboxes: map[string]*collide.Hitbox{},
+	}
+}
+
+// LoadManifest reads manifest.json from the top of fsys and returns a Manager
+// for it.
+func LoadManifest(fsys fs.FS) (*Manager, error) {
+	data, err := fs.ReadFile(fsys, "manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	manifest := Manifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return NewManager(fsys, manifest), nil
+}
+
+// FS is the filesystem the manager loads from, for other kinds of asset
+// that live beside the pictures.
+func (m *Manager) FS() fs.FS {
+	return m.fsys
+}
+
+// Preload loads everything in the manifest, hitboxes included.
+func (m *Manager) Preload() error {
+	errs := Errors{}
+	for name := range m.manifest {
+		if _, err := m.Picture(name); err != nil {
+			errs = append(errs, err.(*Error))
+			continue
+		}
+		if _, err := m.Hitbox(name); err != nil {
+			errs = append(errs, err.(*Error))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Picture returns the named picture, loading it the first time it's asked
+// for. Failures are *Error.
+func (m *Manager) Picture(name string) (pixel.Picture, error) {
+	if pic, ok := m.pictures[name]; ok {
+		return pic, nil
+	}
+
+	path, ok := m.manifest[name]
+	if !ok {
+		return nil, &Error{Kind: Unknown, Name: name}
+	}
+	path = cleanPath(path)
+
+	pic, err := loadPicture(m.fsys, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &Error{Kind: Missing, Name: name, Path: path, Err: err}
+	}
+	if err != nil {
+		return nil, &Error{Kind: Corrupt, Name: name, Path: path, Err: err}
+	}
+
+	m.pictures[name] = pic
+	return pic, nil
+}
+
+// Sprite returns a sprite covering the whole named picture. Every caller gets
+// the same *pixel.Sprite.
+func (m *Manager) Sprite(name string) (*pixel.Sprite, error) {
+	if sprite, ok := m.sprites[name]; ok {
+		return sprite, nil
+	}
+
+	pic, err := m.Picture(name)
+	if err != nil {
+		return nil, err
+	}
+	sprite := pixel.NewSprite(pic, pic.Bounds())
+	m.sprites[name] = sprite
+	return sprite, nil
+}
+
+// Hitbox returns the hitbox defined for the named picture in a .hitbox.json
+// file beside it, such as player.hitbox.json for player.png. It is nil, meaning
+// the whole frame, when there's no such file.
+func (m *Manager) Hitbox(name string) (*collide.Hitbox, error) {
+	if h, ok := m.hitboxes[name]; ok {
+		return h, nil
+	}
+
+	pic, err := m.Picture(name)
+	if err != nil {
+		return nil, err
+	}
+	p := cleanPath(m.manifest[name])
+	p = strings.TrimSuffix(p, path.Ext(p)) + ".hitbox.json"
+
+	data, err := fs.ReadFile(m.fsys, p)
+	if errors.Is(err, fs.ErrNotExist) {
+		m.hitboxes[name] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &Error{Kind: Corrupt, Name: name, Path: p, Err: err}
+	}
+
+	h, err := collide.ParseHitbox(data)
+	if err == nil {
+		err = h.Prepare(pic)
+	}
+	if err != nil {
+		return nil, &Error{Kind: Corrupt, Name: name, Path: p, Err: err}
+	}
+	m.hitboxes[name] = h
+	return h, nil
+}
+
+func cleanPath(p string) string {
+	return path.Clean(path.Join(".", p))
+}
+
+func loadPicture(fsys fs.FS, path string) (pixel.Picture, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return pixel.PictureDataFromImage(img), nil
+}
@@ -0,0 +1,30 @@
+package assets
+
+import (
+	"errors"
+	"io/fs"
+)
+
+type overlay []fs.FS
+
+// Overlay layers filesystems on top of each other. Opening a file tries each
+// layer in order and returns the first one that has it, so a directory of
+// loose files can stand in for individual files of the built-in bundle.
+func Overlay(layers ...fs.FS) fs.FS {
+	return overlay(layers)
+}
+
+func (o overlay) Open(name string) (fs.File, error) {
+	var err error = &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	for _, layer := range o {
+		file, openErr := layer.Open(name)
+		if openErr == nil {
+			return file, nil
+		}
+		if !errors.Is(openErr, fs.ErrNotExist) {
+			return nil, openErr
+		}
+		err = openErr
+	}
+	return nil, err
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+// camera shows the fixed-size world in a window of any size. It scales the
+// world as large as fits without changing its shape, centres it, and covers
+// the rest of the window with bars.
+type camera struct {
+	world pixel.Rect
+	// integer keeps the scale to whole numbers once the window is at least
+	// the world's size, so each world pixel covers the same number of screen
+	// pixels and sprites stay crisp.
+	integer bool
+
+	window   pixel.Rect
+	scale    float64
+	viewport pixel.Rect
+	bars     *imdraw.IMDraw
+}
+
+func newCamera(world pixel.Rect, integer bool) *camera {
+	return &camera{world: world, integer: integer, bars: imdraw.New(nil)}
+}
+
+// fit works out the scale and viewport for a window covering window. It only
+// does any work when the window has changed size.
+func (c *camera) fit(window pixel.Rect) {
+	if window == c.window && c.scale != 0 {
+		return
+	}
+	c.window = window
+
+	c.scale = math.Min(window.W()/c.world.W(), window.H()/c.world.H())
+	if c.integer && c.scale >= 1 {
+		c.scale = math.Floor(c.scale)
+	}
+	size := c.world.Size().Scaled(c.scale)
+	c.viewport = pixel.Rect{Min: window.Center().Sub(size.Scaled(0.5)), Max: window.Center().Add(size.Scaled(0.5))}
+
+	c.bars.Clear()
+	c.bars.Color = colornames.Black
+	for _, bar := range []pixel.Rect{
+		pixel.R(window.Min.X, window.Min.Y, c.viewport.Min.X, window.Max.Y),
+		pixel.R(c.viewport.Max.X, window.Min.Y, window.Max.X, window.Max.Y),
+		pixel.R(c.viewport.Min.X, window.Min.Y, c.viewport.Max.X, c.viewport.Min.Y),
+		pixel.R(c.viewport.Min.X, c.viewport.Max.Y, c.viewport.Max.X, window.Max.Y),
+	} {
+		if bar.W() > 0 && bar.H() > 0 {
+			c.bars.Push(bar.Min, bar.Max)
+			c.bars.Rectangle(0)
+		}
+	}
+}
+
+// matrix takes world coordinates to window coordinates.
+func (c *camera) matrix() pixel.Matrix {
+	return pixel.IM.Moved(c.world.Min.Scaled(-1)).Scaled(pixel.ZV, c.scale).Moved(c.viewport.Min)
+}
+
+// unproject takes a point in the window, such as the mouse, to the world.
+func (c *camera) unproject(v pixel.Vec) pixel.Vec {
+	return c.matrix().Unproject(v)
+}
+
+// begin points win at the world for the scenes to draw in.
+func (c *camera) begin(win *pixelgl.Window) {
+	c.fit(win.Bounds())
+	win.SetMatrix(c.matrix())
+}
+
+// end covers whatever the scenes drew outside the world.
+func (c *camera) end(win *pixelgl.Window) {
+	win.SetMatrix(pixel.IM)
+	c.bars.Draw(win)
+}
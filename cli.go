@@ -0,0 +1,211 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/TheKaterTot/pixelTest/assets"
+	"github.com/TheKaterTot/pixelTest/config"
+	"github.com/TheKaterTot/pixelTest/sim"
+	"github.com/faiface/pixel"
+)
+
+// Exit codes. Usage means the command line itself was wrong.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// command is one of pixelTest's subcommands. args describes its positional
+// arguments for the usage line.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(cmd *command, args []string) int
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{name: "play", summary: "play the game (the default when no command is given)", run: playCommand},
+		{name: "replay", args: "<file>", summary: "watch a recorded game", run: replayCommand},
+		{name: "simulate", summary: "run games without a window and report how they went", run: simulateCommand},
+		{name: "validate", summary: "check the config, assets, bindings and saves for problems", run: validateCommand},
+		{name: "help", args: "[command]", summary: "show help for pixelTest or one of its commands", run: helpCommand},
+	}
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// dispatch runs the command named by args and returns the exit code. Flags
+// with no command in front of them go to play, so plain `pixelTest` and
+// `pixelTest -seed 4` both start a game.
+func dispatch(args []string) int {
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && !isHelpFlag(args[0])) {
+		return playCommand(findCommand("play"), args)
+	}
+	if isHelpFlag(args[0]) {
+		printUsage(os.Stdout)
+		return exitOK
+	}
+
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "pixelTest: unknown command %q\nRun 'pixelTest help' for usage.\n", args[0])
+		return exitUsage
+	}
+	return cmd.run(cmd, args[1:])
+}
+
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: pixelTest <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'pixelTest help <command>' for a command's flags.")
+}
+
+func helpCommand(cmd *command, args []string) int {
+	switch len(args) {
+	case 0:
+		printUsage(os.Stdout)
+		return exitOK
+	case 1:
+		// Help on help is the one command that can't answer -h itself, since
+		// it would take -h for a command's name.
+		if isHelpFlag(args[0]) || args[0] == cmd.name {
+			cmd.printUsage(os.Stdout, cmd.flagSet())
+			return exitOK
+		}
+		target := findCommand(args[0])
+		if target == nil {
+			return usageError(cmd, "unknown command %q", args[0])
+		}
+		return target.run(target, []string{"-h"})
+	}
+	return usageError(cmd, "too many arguments")
+}
+
+func (cmd *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func (cmd *command) printUsage(w io.Writer, fs *flag.FlagSet) {
+	line := "usage: pixelTest " + cmd.name
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		line += " [flags]"
+	}
+	if cmd.args != "" {
+		line += " " + cmd.args
+	}
+	fmt.Fprintln(w, line)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.ToUpper(cmd.summary[:1])+cmd.summary[1:]+".")
+	if hasFlags {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "flags:")
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+		fs.SetOutput(io.Discard)
+	}
+}
+
+// parse parses args into fs. When it returns false the command should stop
+// and exit with code: asked for help, the usage has gone to stdout; given bad
+// flags, the problem and the usage have gone to stderr.
+func (cmd *command) parse(fs *flag.FlagSet, args []string) (code int, ok bool) {
+	err := fs.Parse(args)
+	if err == flag.ErrHelp {
+		cmd.printUsage(os.Stdout, fs)
+		return exitOK, false
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pixelTest %s: %v\n", cmd.name, err)
+		cmd.printUsage(os.Stderr, fs)
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+// usageError reports a command line the command can't make sense of.
+func usageError(cmd *command, format string, args ...interface{}) int {
+	fmt.Fprintf(os.Stderr, "pixelTest %s: %s\nRun 'pixelTest help %s' for usage.\n", cmd.name, fmt.Sprintf(format, args...), cmd.name)
+	return exitUsage
+}
+
+// fail reports something that went wrong while carrying the command out.
+func fail(cmd *command, err error) int {
+	fmt.Fprintf(os.Stderr, "pixelTest %s: %v\n", cmd.name, err)
+	return exitFailure
+}
+
+// commonFlags are the flags every command that loads the game takes.
+type commonFlags struct {
+	config string
+	assets string
+}
+
+func addCommonFlags(fs *flag.FlagSet) *commonFlags {
+	c := &commonFlags{}
+	fs.StringVar(&c.config, "config", "", "game config file to use in place of the one in the user config directory")
+	fs.StringVar(&c.assets, "assets", "", "directory of asset files that override the built-in ones (or set "+assetsEnv+")")
+	return c
+}
+
+// configFile is the config to read, and whether it has to exist: a file
+// named with --config does, the player's own config file doesn't.
+func (c *commonFlags) configFile() (path string, required bool, err error) {
+	if c.config != "" {
+		return c.config, true, nil
+	}
+	path, err = config.Path()
+	return path, false, err
+}
+
+func (c *commonFlags) loadConfig() (config.Config, error) {
+	path, required, err := c.configFile()
+	if err != nil {
+		return config.Default(), err
+	}
+	return config.Load(path, required)
+}
+
+func (c *commonFlags) loadAssets() (*assets.Manager, map[string]*pixel.Sprite, map[string]sim.SpriteDef, error) {
+	fsys, err := assetFS(c.assets)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	m, err := assets.LoadManifest(fsys)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sprites, defs, err := loadSprites(m)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return m, sprites, defs, nil
+}
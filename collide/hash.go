@@ -0,0 +1,97 @@
+// Package collide finds which entities touch. Hash is the broadphase: it
+// buckets bounding boxes into a uniform grid so only boxes sharing a cell are
+// ever compared.
+package collide
+
+import (
+	"math"
+	"sort"
+
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/faiface/pixel"
+)
+
+// Pair is two entities whose boxes overlap, with A < B.
+type Pair struct {
+	A, B ecs.Entity
+}
+
+type cell struct {
+	x, y int
+}
+
+type box struct {
+	e ecs.Entity
+	r pixel.Rect
+}
+
+type Hash struct {
+	size  float64
+	cells map[cell][]int
+	boxes []box
+}
+
+// NewHash makes a Hash with square cells size units wide. Cells a little
+// bigger than the typical entity keep most boxes in one to four cells.
+func NewHash(size float64) *Hash {
+	return &Hash{size: size, cells: map[cell][]int{}}
+}
+
+// Clear empties the hash while keeping its memory for the next step.
+func (h *Hash) Clear() {
+	for c, ids := range h.cells {
+		h.cells[c] = ids[:0]
+	}
+	h.boxes = h.boxes[:0]
+}
+
+func (h *Hash) Insert(e ecs.Entity, r pixel.Rect) {
+	i := len(h.boxes)
+	h.boxes = append(h.boxes, box{e, r})
+
+	minX, minY := h.cellOf(r.Min)
+	maxX, maxY := h.cellOf(r.Max)
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			c := cell{x, y}
+			h.cells[c] = append(h.cells[c], i)
+		}
+	}
+}
+
+func (h *Hash) cellOf(v pixel.Vec) (int, int) {
+	return int(math.Floor(v.X / h.size)), int(math.Floor(v.Y / h.size))
+}
+
+// Pairs returns every pair of inserted boxes that overlap, each pair once, in
+// a fixed order.
+func (h *Hash) Pairs() []Pair {
+	seen := map[Pair]bool{}
+	pairs := []Pair{}
+	for _, ids := range h.cells {
+		for i := 0; i < len(ids); i++ {
+			for j := i + 1; j < len(ids); j++ {
+				a, b := h.boxes[ids[i]], h.boxes[ids[j]]
+				if !Overlap(a.r, b.r) {
+					continue
+				}
+				p := Pair{a.e, b.e}
+				if p.A > p.B {
+					p.A, p.B = p.B, p.A
+				}
+				if !seen[p] {
+					seen[p] = true
+					pairs = append(pairs, p)
+				}
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+	return pairs
+}
@@ -0,0 +1,114 @@
+package collide
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/faiface/pixel"
+)
+
+// boxes scatters n ship-sized boxes over an area that grows with n, so the
+// crowding stays about what it is on screen today.
+func boxes(n int) []box {
+	r := rand.New(rand.NewSource(1))
+	side := 150 * math.Sqrt(float64(n))
+	boxes := make([]box, n)
+	for i := range boxes {
+		x, y := r.Float64()*side, r.Float64()*side
+		boxes[i] = box{ecs.Entity(i + 1), pixel.R(x, y, x+94, y+85)}
+	}
+	return boxes
+}
+
+// naivePairs checks every box against every other, in insertion order.
+func naivePairs(boxes []box) []Pair {
+	pairs := []Pair{}
+	for j := range boxes {
+		for k := j + 1; k < len(boxes); k++ {
+			if Overlap(boxes[j].r, boxes[k].r) {
+				pairs = append(pairs, Pair{boxes[j].e, boxes[k].e})
+			}
+		}
+	}
+	return pairs
+}
+
+// mixedBoxes scatters n boxes of every size around the origin, from slivers
+// inside one cell to boxes spanning several, some lined up on cell edges.
+func mixedBoxes(seed int64, n int, cellSize float64) []box {
+	r := rand.New(rand.NewSource(seed))
+	boxes := make([]box, n)
+	for i := range boxes {
+		x, y := (r.Float64()-0.5)*8*cellSize, (r.Float64()-0.5)*8*cellSize
+		if r.Intn(4) == 0 {
+			x, y = math.Floor(x/cellSize)*cellSize, math.Floor(y/cellSize)*cellSize
+		}
+		w, h := r.Float64()*3*cellSize, r.Float64()*3*cellSize
+		boxes[i] = box{ecs.Entity(i + 1), pixel.R(x, y, x+w, y+h)}
+	}
+	return boxes
+}
+
+func TestPairsMatchNaive(t *testing.T) {
+	const size = 64
+	h := NewHash(size)
+	for seed := int64(1); seed <= 20; seed++ {
+		boxes := mixedBoxes(seed, 60, size)
+		// Reusing one hash checks Clear forgets the last step.
+		h.Clear()
+		for _, b := range boxes {
+			h.Insert(b.e, b.r)
+		}
+		// Entities go in in order, so the naive pairs are already sorted with
+		// A < B, and each is there exactly once.
+		if got, want := h.Pairs(), naivePairs(boxes); !reflect.DeepEqual(got, want) {
+			t.Fatalf("seed %d: Pairs gave %d pairs, naive %d:\n got %v\nwant %v", seed, len(got), len(want), got, want)
+		}
+	}
+}
+
+func TestPairsReportsBoxesSharingManyCellsOnce(t *testing.T) {
+	h := NewHash(10)
+	h.Insert(2, pixel.R(0, 0, 100, 100))
+	h.Insert(1, pixel.R(5, 5, 95, 95))
+	want := []Pair{{1, 2}}
+	if got := h.Pairs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pairs = %v, want %v", got, want)
+	}
+}
+
+var boxCounts = []int{10, 100, 1000, 5000}
+
+// BenchmarkNaive is the old approach: every box checked against every other.
+func BenchmarkNaive(b *testing.B) {
+	for _, n := range boxCounts {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			boxes := boxes(n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				naivePairs(boxes)
+			}
+		})
+	}
+}
+
+func BenchmarkHash(b *testing.B) {
+	for _, n := range boxCounts {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			boxes := boxes(n)
+			h := NewHash(128)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				h.Clear()
+				for _, box := range boxes {
+					h.Insert(box.e, box.r)
+				}
+				h.Pairs()
+			}
+		})
+	}
+}
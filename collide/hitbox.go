@@ -0,0 +1,156 @@
+package collide
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/faiface/pixel"
+)
+
+const (
+	RectShape    = "rect"
+	CircleShape  = "circle"
+	PolygonShape = "polygon"
+	MaskShape    = "mask"
+)
+
+// Inset shrinks a rect hitbox in from the frame's edges.
+type Inset struct {
+	Left   float64 `json:"left"`
+	Right  float64 `json:"right"`
+	Top    float64 `json:"top"`
+	Bottom float64 `json:"bottom"`
+}
+
+// Hitbox is a sprite's collision shape as its definition file describes it.
+// Lengths are in picture pixels and points are relative to the centre of the
+// frame, with y up, the same space the sprite is drawn in before scaling.
+type Hitbox struct {
+	Shape     string       `json:"shape"`
+	Inset     Inset        `json:"inset"`
+	Center    [2]float64   `json:"center"`
+	Radius    float64      `json:"radius"`
+	Points    [][2]float64 `json:"points"`
+	Threshold float64      `json:"threshold"`
+
+	mask *maskData
+}
+
+// ParseHitbox reads a hitbox definition and checks it makes sense on its own.
+// Prepare finishes the job once the picture is known.
+func ParseHitbox(data []byte) (*Hitbox, error) {
+	h := &Hitbox{}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+
+	switch h.Shape {
+	case RectShape:
+	case CircleShape:
+		if h.Radius <= 0 {
+			return nil, fmt.Errorf("circle: radius must be positive, got %v", h.Radius)
+		}
+	case PolygonShape:
+		if len(h.Points) < 3 {
+			return nil, fmt.Errorf("polygon: need at least 3 points, got %d", len(h.Points))
+		}
+		if !convex(h.polygon()) {
+			return nil, fmt.Errorf("polygon: points must make a convex shape")
+		}
+		if h.polygon().area() < 0 {
+			for i, j := 0, len(h.Points)-1; i < j; i, j = i+1, j-1 {
+				h.Points[i], h.Points[j] = h.Points[j], h.Points[i]
+			}
+		}
+	case MaskShape:
+		if h.Threshold == 0 {
+			h.Threshold = 0.5
+		}
+		if h.Threshold < 0 || h.Threshold > 1 {
+			return nil, fmt.Errorf("mask: threshold must be between 0 and 1, got %v", h.Threshold)
+		}
+	default:
+		return nil, fmt.Errorf("unknown shape %q", h.Shape)
+	}
+	return h, nil
+}
+
+// Prepare ties the hitbox to the picture it belongs to, checking it fits the
+// frame and building the alpha mask for mask hitboxes.
+func (h *Hitbox) Prepare(pic pixel.Picture) error {
+	frame := pic.Bounds()
+
+	switch h.Shape {
+	case RectShape:
+		if h.Inset.Left+h.Inset.Right >= frame.W() || h.Inset.Top+h.Inset.Bottom >= frame.H() {
+			return fmt.Errorf("rect: inset leaves nothing of the %vx%v frame", frame.W(), frame.H())
+		}
+	case MaskShape:
+		pd, ok := pic.(*pixel.PictureData)
+		if !ok {
+			return fmt.Errorf("mask: picture has no pixel data")
+		}
+		h.mask = newMaskData(pd, frame, h.Threshold)
+	}
+	return nil
+}
+
+func (h *Hitbox) polygon() Polygon {
+	p := Polygon{}
+	for _, pt := range h.Points {
+		p = append(p, pixel.V(pt[0], pt[1]))
+	}
+	return p
+}
+
+// Place puts the hitbox in the world for a sprite with the given frame drawn
+// at pos and scale. A nil Hitbox covers the whole frame.
+func (h *Hitbox) Place(frame pixel.Rect, pos pixel.Vec, scale float64) Shape {
+	if h == nil {
+		half := frame.Size().Scaled(scale / 2)
+		return rectPolygon(pixel.Rect{Min: pos.Sub(half), Max: pos.Add(half)})
+	}
+
+	switch h.Shape {
+	case CircleShape:
+		return Circle{Center: pos.Add(pixel.V(h.Center[0], h.Center[1]).Scaled(scale)), Radius: h.Radius * scale}
+	case PolygonShape:
+		p := Polygon{}
+		for _, v := range h.polygon() {
+			p = append(p, pos.Add(v.Scaled(scale)))
+		}
+		return p
+	case MaskShape:
+		return &Mask{data: h.mask, center: pos, scale: scale}
+	}
+
+	half := frame.Size().Scaled(0.5)
+	r := pixel.R(-half.X+h.Inset.Left, -half.Y+h.Inset.Bottom, half.X-h.Inset.Right, half.Y-h.Inset.Top)
+	return rectPolygon(pixel.Rect{Min: pos.Add(r.Min.Scaled(scale)), Max: pos.Add(r.Max.Scaled(scale))})
+}
+
+// area is the polygon's signed area, positive when its points run
+// counter-clockwise.
+func (p Polygon) area() float64 {
+	a := 0.0
+	for i := range p {
+		a += p[i].Cross(p[(i+1)%len(p)])
+	}
+	return a / 2
+}
+
+func convex(p Polygon) bool {
+	sign := 0.0
+	for i := range p {
+		a, b, c := p[i], p[(i+1)%len(p)], p[(i+2)%len(p)]
+		cross := b.Sub(a).Cross(c.Sub(b))
+		if cross == 0 {
+			continue
+		}
+		if sign != 0 && (cross > 0) != (sign > 0) {
+			return false
+		}
+		sign = cross
+	}
+	return sign != 0
+}
@@ -0,0 +1,64 @@
+package collide
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// maskStep is how far apart, in world units, Collide samples the overlap of a
+// Mask with another shape.
+const maskStep = 1.0
+
+type maskData struct {
+	bits []bool
+	w, h int
+}
+
+func newMaskData(pd *pixel.PictureData, frame pixel.Rect, threshold float64) *maskData {
+	w, h := int(frame.W()), int(frame.H())
+	m := &maskData{bits: make([]bool, w*h), w: w, h: h}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			at := frame.Min.Add(pixel.V(float64(x)+0.5, float64(y)+0.5))
+			m.bits[y*w+x] = pd.Color(at).A >= threshold
+		}
+	}
+	return m
+}
+
+// Mask is a sprite's opaque pixels placed in the world: a hit only counts
+// where the picture isn't see-through.
+type Mask struct {
+	data   *maskData
+	center pixel.Vec
+	scale  float64
+}
+
+func (m *Mask) Bounds() pixel.Rect {
+	half := pixel.V(float64(m.data.w), float64(m.data.h)).Scaled(m.scale / 2)
+	return pixel.Rect{Min: m.center.Sub(half), Max: m.center.Add(half)}
+}
+
+func (m *Mask) Contains(v pixel.Vec) bool {
+	local := v.Sub(m.center).Scaled(1 / m.scale)
+	x := int(math.Floor(local.X + float64(m.data.w)/2))
+	y := int(math.Floor(local.Y + float64(m.data.h)/2))
+	if x < 0 || y < 0 || x >= m.data.w || y >= m.data.h {
+		return false
+	}
+	return m.data.bits[y*m.data.w+x]
+}
+
+func (m *Mask) collide(other Shape) bool {
+	area := m.Bounds().Intersect(other.Bounds())
+	for y := area.Min.Y + maskStep/2; y < area.Max.Y; y += maskStep {
+		for x := area.Min.X + maskStep/2; x < area.Max.X; x += maskStep {
+			v := pixel.V(x, y)
+			if m.Contains(v) && other.Contains(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package collide
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+// halfMask is a mask for a 4x4 picture whose left half is opaque, placed at
+// the origin.
+func halfMask(t *testing.T) *Mask {
+	t.Helper()
+	pd := pixel.MakePictureData(pixel.R(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 2; x++ {
+			pd.Pix[y*pd.Stride+x] = color.RGBA{A: 255}
+		}
+	}
+	h, err := ParseHitbox([]byte(`{"shape": "mask"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Prepare(pd); err != nil {
+		t.Fatal(err)
+	}
+	return h.Place(pd.Bounds(), pixel.ZV, 1).(*Mask)
+}
+
+func TestMaskContainsOpaquePixels(t *testing.T) {
+	m := halfMask(t)
+	for _, c := range []struct {
+		at   pixel.Vec
+		want bool
+	}{
+		{pixel.V(-1.5, -1.5), true},
+		{pixel.V(-0.5, 1.5), true},
+		{pixel.V(0.5, 0), false},
+		{pixel.V(1.5, 1.5), false},
+		{pixel.V(-2.5, 0), false},
+	} {
+		if got := m.Contains(c.at); got != c.want {
+			t.Errorf("Contains(%v) = %v, want %v", c.at, got, c.want)
+		}
+	}
+}
+
+func TestMaskCollide(t *testing.T) {
+	m := halfMask(t)
+	for _, c := range []struct {
+		name  string
+		shape Shape
+		want  bool
+	}{
+		{"square over the opaque half", square(-1, -1, 2), true},
+		// Inside the mask's bounds, but only over see-through pixels.
+		{"square over the clear half", square(0.5, -1, 1), false},
+		{"circle over the opaque half", Circle{pixel.V(-2, 0), 1}, true},
+		{"circle over the clear half", Circle{pixel.V(1.5, 0), 0.5}, false},
+		{"square outside", square(5, 5, 2), false},
+	} {
+		if got := Collide(m, c.shape); got != c.want {
+			t.Errorf("%s: Collide = %v, want %v", c.name, got, c.want)
+		}
+		if got := Collide(c.shape, m); got != c.want {
+			t.Errorf("%s, swapped: Collide = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestMasksCollide(t *testing.T) {
+	a := halfMask(t)
+	// Moved right by three, b's opaque half starts where a's clear half ends.
+	b := &Mask{data: a.data, center: pixel.V(3, 0), scale: 1}
+	if Collide(a, b) {
+		t.Error("masks touching only clear pixels collided")
+	}
+	b.center = pixel.V(1, 0)
+	if !Collide(a, b) {
+		t.Error("overlapping opaque halves didn't collide")
+	}
+}
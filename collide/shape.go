@@ -0,0 +1,140 @@
+package collide
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Shape is a hitbox placed in the world.
+type Shape interface {
+	Bounds() pixel.Rect
+	Contains(v pixel.Vec) bool
+}
+
+type Circle struct {
+	Center pixel.Vec
+	Radius float64
+}
+
+func (c Circle) Bounds() pixel.Rect {
+	return pixel.R(c.Center.X-c.Radius, c.Center.Y-c.Radius, c.Center.X+c.Radius, c.Center.Y+c.Radius)
+}
+
+func (c Circle) Contains(v pixel.Vec) bool {
+	return v.Sub(c.Center).Len() < c.Radius
+}
+
+// Polygon is a convex polygon with its points in counter-clockwise order.
+type Polygon []pixel.Vec
+
+func rectPolygon(r pixel.Rect) Polygon {
+	return Polygon{r.Min, pixel.V(r.Max.X, r.Min.Y), r.Max, pixel.V(r.Min.X, r.Max.Y)}
+}
+
+func (p Polygon) Bounds() pixel.Rect {
+	r := pixel.R(p[0].X, p[0].Y, p[0].X, p[0].Y)
+	for _, v := range p[1:] {
+		r.Min.X = math.Min(r.Min.X, v.X)
+		r.Min.Y = math.Min(r.Min.Y, v.Y)
+		r.Max.X = math.Max(r.Max.X, v.X)
+		r.Max.Y = math.Max(r.Max.Y, v.Y)
+	}
+	return r
+}
+
+func (p Polygon) Contains(v pixel.Vec) bool {
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		if b.Sub(a).Cross(v.Sub(a)) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// project returns the interval the polygon covers along axis.
+func (p Polygon) project(axis pixel.Vec) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range p {
+		d := v.Dot(axis)
+		min = math.Min(min, d)
+		max = math.Max(max, d)
+	}
+	return min, max
+}
+
+// Overlap reports whether two rectangles share some area. Rectangles that
+// only touch along an edge don't.
+func Overlap(r, r2 pixel.Rect) bool {
+	intersection := r.Intersect(r2)
+	return intersection.W() > 0 && intersection.H() > 0
+}
+
+// Collide reports whether two shapes overlap. Circles and polygons are tested
+// exactly; anything involving a Mask is tested pixel by pixel.
+func Collide(a, b Shape) bool {
+	if !Overlap(a.Bounds(), b.Bounds()) {
+		return false
+	}
+
+	switch a := a.(type) {
+	case Circle:
+		switch b := b.(type) {
+		case Circle:
+			return a.Center.Sub(b.Center).Len() < a.Radius+b.Radius
+		case Polygon:
+			return circlePolygon(a, b)
+		}
+	case Polygon:
+		switch b := b.(type) {
+		case Circle:
+			return circlePolygon(b, a)
+		case Polygon:
+			return polygons(a, b)
+		}
+	case *Mask:
+		return a.collide(b)
+	}
+	if m, ok := b.(*Mask); ok {
+		return m.collide(a)
+	}
+	return false
+}
+
+// polygons is the separating axis test: two convex polygons are apart exactly
+// when some edge normal separates their projections.
+func polygons(a, b Polygon) bool {
+	for _, p := range []Polygon{a, b} {
+		for i := range p {
+			axis := p[(i+1)%len(p)].Sub(p[i]).Normal()
+			minA, maxA := a.project(axis)
+			minB, maxB := b.project(axis)
+			if maxA <= minB || maxB <= minA {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func circlePolygon(c Circle, p Polygon) bool {
+	if p.Contains(c.Center) {
+		return true
+	}
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		if closest(a, b, c.Center).Sub(c.Center).Len() < c.Radius {
+			return true
+		}
+	}
+	return false
+}
+
+// closest is the point on segment ab nearest to v.
+func closest(a, b, v pixel.Vec) pixel.Vec {
+	ab := b.Sub(a)
+	t := v.Sub(a).Dot(ab) / ab.Dot(ab)
+	t = math.Max(0, math.Min(1, t))
+	return a.Add(ab.Scaled(t))
+}
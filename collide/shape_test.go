@@ -0,0 +1,89 @@
+package collide
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+func square(x, y, side float64) Polygon {
+	return rectPolygon(pixel.R(x, y, x+side, y+side))
+}
+
+// diamond is a square turned on its corner, so its edges aren't
+// axis-aligned like its bounds.
+func diamond(x, y, r float64) Polygon {
+	return Polygon{pixel.V(x, y-r), pixel.V(x+r, y), pixel.V(x, y+r), pixel.V(x-r, y)}
+}
+
+func TestCollide(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		a, b Shape
+		want bool
+	}{
+		{"overlapping squares", square(0, 0, 10), square(5, 5, 10), true},
+		{"square inside square", square(0, 0, 10), square(2, 2, 2), true},
+		{"squares sharing an edge", square(0, 0, 10), square(10, 0, 10), false},
+		{"apart squares", square(0, 0, 10), square(20, 0, 10), false},
+		// The bounds overlap, but the diamonds' slanted edges keep them apart.
+		{"diamonds corner to corner", diamond(0, 0, 10), diamond(14, 14, 10), false},
+		{"diamonds overlapping", diamond(0, 0, 10), diamond(9, 9, 10), true},
+		{"triangle beside square's bounds", Polygon{pixel.V(0, 0), pixel.V(10, 0), pixel.V(0, 10)}, square(6, 6, 4), false},
+		{"triangle into square", Polygon{pixel.V(0, 0), pixel.V(10, 0), pixel.V(0, 10)}, square(4, 4, 4), true},
+
+		{"circle inside square", Circle{pixel.V(5, 5), 1}, square(0, 0, 10), true},
+		{"circle over edge", Circle{pixel.V(12, 5), 3}, square(0, 0, 10), true},
+		{"circle off edge", Circle{pixel.V(14, 5), 3}, square(0, 0, 10), false},
+		// Within the square's bounds on both axes, but past the corner.
+		{"circle off corner", Circle{pixel.V(12.5, 12.5), 3}, square(0, 0, 10), false},
+		{"circle over corner", Circle{pixel.V(11.5, 11.5), 3}, square(0, 0, 10), true},
+		{"circle off diamond's edge", Circle{pixel.V(7, 7), 2}, diamond(0, 0, 10), false},
+
+		{"circles overlapping", Circle{pixel.V(0, 0), 5}, Circle{pixel.V(8, 0), 5}, true},
+		{"circles touching", Circle{pixel.V(0, 0), 5}, Circle{pixel.V(10, 0), 5}, false},
+	} {
+		if got := Collide(c.a, c.b); got != c.want {
+			t.Errorf("%s: Collide = %v, want %v", c.name, got, c.want)
+		}
+		if got := Collide(c.b, c.a); got != c.want {
+			t.Errorf("%s, swapped: Collide = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParseHitboxChecksPolygons(t *testing.T) {
+	for _, c := range []struct {
+		name, json string
+		ok         bool
+	}{
+		{"counter-clockwise", `{"shape": "polygon", "points": [[0, 0], [10, 0], [0, 10]]}`, true},
+		{"clockwise", `{"shape": "polygon", "points": [[0, 0], [0, 10], [10, 0]]}`, true},
+		{"two points", `{"shape": "polygon", "points": [[0, 0], [10, 0]]}`, false},
+		{"concave", `{"shape": "polygon", "points": [[0, 0], [10, 0], [2, 2], [0, 10]]}`, false},
+		{"all in a line", `{"shape": "polygon", "points": [[0, 0], [5, 0], [10, 0]]}`, false},
+		{"circle without radius", `{"shape": "circle"}`, false},
+		{"mask threshold over 1", `{"shape": "mask", "threshold": 2}`, false},
+		{"unknown shape", `{"shape": "star"}`, false},
+	} {
+		_, err := ParseHitbox([]byte(c.json))
+		if (err == nil) != c.ok {
+			t.Errorf("%s: err = %v, want ok %v", c.name, err, c.ok)
+		}
+	}
+}
+
+func TestParseHitboxWindsCounterClockwise(t *testing.T) {
+	h, err := ParseHitbox([]byte(`{"shape": "polygon", "points": [[0, 0], [0, 10], [10, 0]]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.polygon().area() <= 0 {
+		t.Errorf("points %v still run clockwise", h.Points)
+	}
+	// Contains relies on the winding; a clockwise polygon contains nothing.
+	p := h.Place(pixel.R(0, 0, 10, 10), pixel.ZV, 1)
+	if !p.Contains(pixel.V(2, 2)) {
+		t.Error("placed polygon doesn't contain a point inside it")
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/TheKaterTot/pixelTest/assets"
+	"github.com/TheKaterTot/pixelTest/config"
+	"github.com/TheKaterTot/pixelTest/display"
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/i18n"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/replay"
+	"github.com/TheKaterTot/pixelTest/saves"
+	"github.com/TheKaterTot/pixelTest/scores"
+	"github.com/TheKaterTot/pixelTest/sim"
+)
+
+// gameFlags are the flags that decide what game gets played, shared by play
+// and simulate.
+type gameFlags struct {
+	difficulty string
+}
+
+func addGameFlags(fs *flag.FlagSet) *gameFlags {
+	g := &gameFlags{}
+	fs.StringVar(&g.difficulty, "difficulty", "normal", "how hard the enemies are: easy, normal or hard")
+	return g
+}
+
+// apply lays the flags over conf. Its errors are the command line's fault.
+func (g *gameFlags) apply(conf *config.Config) error {
+	if err := conf.ApplyDifficulty(g.difficulty); err != nil {
+		return err
+	}
+	return conf.Validate()
+}
+
+// sizeFlags override a width and height from the config. Play sizes the
+// window with them; simulate has no window, so it sizes the world.
+type sizeFlags struct {
+	width, height int
+}
+
+func addSizeFlags(fs *flag.FlagSet, what string) *sizeFlags {
+	f := &sizeFlags{}
+	fs.IntVar(&f.width, "width", 0, what+" width, in place of the config's")
+	fs.IntVar(&f.height, "height", 0, what+" height, in place of the config's")
+	return f
+}
+
+func playCommand(cmd *command, args []string) int {
+	fs := cmd.flagSet()
+	common := addCommonFlags(fs)
+	game := addGameFlags(fs)
+	size := addSizeFlags(fs, "window")
+	fullscreen := fs.Bool("fullscreen", false, "play fullscreen this time, whatever the display settings say")
+	seed := fs.Int64("seed", 0, "seed every game with this in place of the clock")
+	language := fs.String("language", "", "language code to play in this time, such as es, in place of the saved one")
+	if code, ok := cmd.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return usageError(cmd, "unexpected argument %q", fs.Arg(0))
+	}
+
+	conf, err := common.loadConfig()
+	if err != nil {
+		return fail(cmd, err)
+	}
+	if size.width != 0 {
+		conf.Window.Width = size.width
+	}
+	if size.height != 0 {
+		conf.Window.Height = size.height
+	}
+	if err := game.apply(&conf); err != nil {
+		return usageError(cmd, "%v", err)
+	}
+	m, sprites, defs, err := common.loadAssets()
+	if err != nil {
+		return fail(cmd, err)
+	}
+	languages, err := i18n.LoadFS(m.FS(), langDir)
+	if err != nil {
+		return fail(cmd, err)
+	}
+	if *language != "" && languages.Get(*language) == nil {
+		return usageError(cmd, "no catalog for language %q; have %s", *language, strings.Join(languages.Codes(), ", "))
+	}
+
+	return start(&session{
+		config:     conf,
+		fullscreen: *fullscreen,
+		seed:       *seed,
+		difficulty: game.difficulty,
+		language:   *language,
+		languages:  languages,
+		assets:     m,
+		sprites:    sprites,
+		spriteDefs: defs,
+	})
+}
+
+func replayCommand(cmd *command, args []string) int {
+	fs := cmd.flagSet()
+	common := addCommonFlags(fs)
+	if code, ok := cmd.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 1 {
+		return usageError(cmd, "want one replay file, got %d arguments", fs.NArg())
+	}
+
+	conf, err := common.loadConfig()
+	if err != nil {
+		return fail(cmd, err)
+	}
+	r, err := replay.Load(fs.Arg(0))
+	if err != nil {
+		return fail(cmd, err)
+	}
+	// The recording plays in the world it was made in.
+	conf.World.Width = r.Bounds.W()
+	conf.World.Height = r.Bounds.H()
+	m, sprites, defs, err := common.loadAssets()
+	if err != nil {
+		return fail(cmd, err)
+	}
+	languages, err := i18n.LoadFS(m.FS(), langDir)
+	if err != nil {
+		return fail(cmd, err)
+	}
+
+	return start(&session{
+		config:     conf,
+		languages:  languages,
+		assets:     m,
+		sprites:    sprites,
+		spriteDefs: defs,
+		replay:     r,
+	})
+}
+
+// simulateCommand plays a game with no window, either from a recording or
+// with a bot at the controls, and prints how it ended.
+func simulateCommand(cmd *command, args []string) int {
+	fs := cmd.flagSet()
+	common := addCommonFlags(fs)
+	game := addGameFlags(fs)
+	size := addSizeFlags(fs, "world")
+	seed := fs.Int64("seed", 1, "seed for the game and the bot")
+	ticks := fs.Int("ticks", 5*60*60, "stop after this many ticks if the game hasn't ended")
+	bot := fs.String("input", "random", "what the bot does: idle or random")
+	from := fs.String("replay", "", "play this recording in place of a bot, and check it ends on its recorded score")
+	record := fs.String("record", "", "write the bot's game to this replay file")
+	if code, ok := cmd.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return usageError(cmd, "unexpected argument %q", fs.Arg(0))
+	}
+	if *ticks <= 0 {
+		return usageError(cmd, "-ticks must be positive")
+	}
+	next, err := newBot(*bot, *seed)
+	if err != nil {
+		return usageError(cmd, "%v", err)
+	}
+
+	_, _, defs, err := common.loadAssets()
+	if err != nil {
+		return fail(cmd, err)
+	}
+
+	if *from != "" {
+		r, err := replay.Load(*from)
+		if err != nil {
+			return fail(cmd, err)
+		}
+		g, err := r.Play(defs)
+		fmt.Printf("%s: score %d after %d ticks, recorded %d\n", *from, g.Score, len(r.Inputs), r.Score)
+		if err != nil {
+			return fail(cmd, err)
+		}
+		return exitOK
+	}
+
+	conf, err := common.loadConfig()
+	if err != nil {
+		return fail(cmd, err)
+	}
+	if size.width != 0 {
+		conf.World.Width = float64(size.width)
+	}
+	if size.height != 0 {
+		conf.World.Height = float64(size.height)
+	}
+	if err := game.apply(&conf); err != nil {
+		return usageError(cmd, "%v", err)
+	}
+
+	bounds := conf.World.Bounds()
+	g := sim.New(bounds, defs, conf.Game, *seed)
+	rec := &replay.Replay{Seed: *seed, Bounds: bounds, Tuning: conf.Game}
+	for tick := 0; tick < *ticks && g.Running; tick++ {
+		in := next(tick)
+		rec.Record(in)
+		g.Step(in)
+	}
+	rec.Score = g.Score
+
+	ending := "still running"
+	if !g.Running {
+		ending = "game over"
+	}
+	fmt.Printf("seed %d: score %d after %d ticks (%.1fs), %s\n", *seed, g.Score, len(rec.Inputs), float64(len(rec.Inputs))*sim.Tick, ending)
+
+	if *record != "" {
+		if err := rec.Save(*record); err != nil {
+			return fail(cmd, err)
+		}
+	}
+	return exitOK
+}
+
+// botHold is how many ticks the random bot holds a direction for.
+const botHold = 30
+
+// newBot returns what the named bot presses on each tick.
+func newBot(name string, seed int64) (func(tick int) sim.Input, error) {
+	switch name {
+	case "idle":
+		return func(int) sim.Input { return sim.Input{} }, nil
+	case "random":
+		rng := sim.NewRNG(^seed)
+		held := sim.Input{}
+		return func(tick int) sim.Input {
+			if tick%botHold == 0 {
+				bits := rng.Uint64()
+				held = sim.Input{Left: bits&1 != 0, Right: bits&2 != 0, Up: bits&4 != 0, Down: bits&8 != 0}
+			}
+			in := held
+			in.Fire = rng.Float64() < 0.25
+			in.Firing = in.Fire
+			return in
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown input %q (want idle or random)", name)
+}
+
+// validateCommand loads everything a game would and reports each problem,
+// without opening a window.
+func validateCommand(cmd *command, args []string) int {
+	fs := cmd.flagSet()
+	common := addCommonFlags(fs)
+	if code, ok := cmd.parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		return usageError(cmd, "unexpected argument %q", fs.Arg(0))
+	}
+
+	failed := false
+	report := func(what string, err error) {
+		if err == nil {
+			fmt.Printf("ok      %s\n", what)
+			return
+		}
+		failed = true
+		var errs assets.Errors
+		if !errors.As(err, &errs) {
+			fmt.Printf("FAIL    %s: %v\n", what, err)
+			return
+		}
+		fmt.Printf("FAIL    %s:\n", what)
+		for _, err := range errs {
+			fmt.Printf("          %v\n", err)
+		}
+	}
+
+	path, required, err := common.configFile()
+	if err == nil {
+		_, err = config.Load(path, required)
+	}
+	report("config "+path, err)
+
+	m, _, defs, err := common.loadAssets()
+	report("assets", err)
+	if m != nil {
+		report("fonts", hud.NewFonts().LoadFS(m.FS(), fontsDir))
+		languages, err := i18n.LoadFS(m.FS(), langDir)
+		report("languages", err)
+		if languages != nil {
+			for _, code := range languages.Codes() {
+				if missing := languages.Missing(code); len(missing) > 0 {
+					fmt.Printf("warn    language %s: %d untranslated, shown in %s: %s\n", code, len(missing), i18n.Default, strings.Join(missing, ", "))
+				}
+			}
+		}
+	}
+
+	path, err = scores.Path()
+	if err == nil {
+		_, err = scores.Read(path)
+	}
+	report("scores "+path, err)
+
+	path, err = i18n.Path()
+	if err == nil {
+		_, err = i18n.LoadSetting(path)
+	}
+	report("language "+path, err)
+
+	path, err = input.BindingsPath()
+	if err == nil {
+		_, err = input.LoadBindings(path)
+	}
+	report("bindings "+path, err)
+
+	path, err = display.Path()
+	if err == nil {
+		_, err = display.Load(path)
+	}
+	report("display "+path, err)
+
+	// Saves can only be restored against sprites that loaded.
+	if defs != nil {
+		for _, slot := range saves.Slots {
+			f, err := saves.Read(slot)
+			if f == nil && err == nil {
+				continue
+			}
+			if err == nil {
+				_, err = sim.Restore(f.State, defs)
+			}
+			report("save "+slot, err)
+		}
+	}
+
+	if failed {
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,219 @@
+// Package config loads the tunables designers adjust without recompiling:
+// the world and window sizes and the numbers the simulation plays by. They live in a
+// JSON file, and anything the file leaves out keeps its default.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TheKaterTot/pixelTest/sim"
+	"github.com/faiface/pixel"
+)
+
+type Config struct {
+	World  World      `json:"world"`
+	Window Window     `json:"window"`
+	Game   sim.Tuning `json:"game"`
+}
+
+// World is the size of the play area in world units. It stays the same
+// whatever size the window is.
+type World struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (w World) Bounds() pixel.Rect {
+	return pixel.R(0, 0, w.Width, w.Height)
+}
+
+// Window is the size the window opens at, in pixels. The world is scaled to
+// fit it, with bars filling whatever's left over.
+type Window struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// IntegerScale only scales the world by whole numbers, for crisp pixel
+	// art at the cost of wider bars.
+	IntegerScale bool `json:"integer_scale"`
+}
+
+func Default() Config {
+	return Config{
+		World:  World{Width: 1024, Height: 768},
+		Window: Window{Width: 1024, Height: 768},
+		Game:   sim.DefaultTuning(),
+	}
+}
+
+// Path is where the config is read from when no other file is given.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pixelTest", "config.json"), nil
+}
+
+// Load reads the config at path over the defaults. If required is false, a
+// missing file just gives the defaults.
+func Load(path string, required bool) (Config, error) {
+	c := Default()
+
+	data, err := ioutil.ReadFile(path)
+	if !required && errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+
+	// A list in the file replaces the default one whole. Decoding over the
+	// default would fill whatever each element leaves out from the default
+	// element at the same index.
+	c.Game.Enemies = nil
+	c.Game.Loot.Drops = nil
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return Default(), fmt.Errorf("%s: %s: want a %s, got a %s", path, typeErr.Field, typeErr.Type, typeErr.Value)
+		}
+		return Default(), fmt.Errorf("%s: %v", path, err)
+	}
+	if c.Game.Enemies == nil {
+		c.Game.Enemies = Default().Game.Enemies
+	}
+	if c.Game.Loot.Drops == nil {
+		c.Game.Loot.Drops = Default().Game.Loot.Drops
+	}
+	if err := c.Validate(); err != nil {
+		return Default(), fmt.Errorf("%s: %v", path, err)
+	}
+	return c, nil
+}
+
+// Validate reports the first setting that can't make a playable game, by its
+// key in the file.
+func (c Config) Validate() error {
+	type check struct {
+		key string
+		ok  bool
+	}
+	t := c.Game
+	checks := []check{
+		{"world.width", c.World.Width > 0},
+		{"world.height", c.World.Height > 0},
+		{"window.width", c.Window.Width > 0},
+		{"window.height", c.Window.Height > 0},
+		{"game.padding", t.Padding >= 0 && 2*t.Padding < c.World.Width && 2*t.Padding < c.World.Height},
+		{"game.player_speed", t.PlayerSpeed > 0},
+		{"game.enemy_speed", t.EnemySpeed > 0},
+		{"game.missile_speed", t.MissileSpeed > 0},
+		{"game.enemy_fire_rate", t.EnemyFireRate >= 0},
+		{"game.enemy_missile_mul", t.EnemyMissileMul > 0},
+		{"game.enemy_missile_lifetime", t.EnemyMissileLifetime > 0},
+		{"game.max_enemies", t.MaxEnemies >= 0},
+		{"game.player_scale", t.PlayerScale > 0},
+		{"game.enemy_scale", t.EnemyScale > 0},
+		{"game.missile_scale", t.MissileScale > 0},
+		{"game.lives", t.Lives > 0},
+		{"game.extra_lives", increasing(t.ExtraLives)},
+		{"game.extra_life_every", t.ExtraLifeEvery >= 0},
+		{"game.invulnerable", t.Invulnerable >= 0},
+		{"game.player_health", t.PlayerHealth > 0},
+		{"game.player_damage", t.PlayerDamage > 0},
+		{"game.missile_damage", t.MissileDamage > 0},
+		{"game.enemy_missile_damage", t.EnemyMissileDamage > 0},
+		{"game.hit_flash", t.HitFlash > 0},
+	}
+	for i, e := range t.Enemies {
+		key := fmt.Sprintf("game.enemies[%d].", i)
+		checks = append(checks, []check{
+			{key + "weight", e.Weight > 0},
+			{key + "health", e.Health > 0},
+			{key + "damage", e.Damage > 0},
+			{key + "points", e.Points >= 0},
+			{key + "speed_mul", e.SpeedMul > 0},
+			{key + "fire_rate_mul", e.FireRateMul >= 0},
+			{key + "scale_mul", e.ScaleMul > 0},
+		}...)
+	}
+	l := t.Loot
+	if len(l.Drops) > 0 {
+		checks = append(checks, []check{
+			{"game.loot.lifetime", l.Lifetime > 0},
+			{"game.loot.warning", l.Warning >= 0},
+			{"game.loot.drift", l.Drift >= 0},
+			{"game.loot.radius", l.Radius > 0},
+			{"game.loot.spread_angle", l.SpreadAngle >= 0},
+			{"game.loot.rapid_fire_rate", l.RapidFireRate > 0},
+		}...)
+	}
+	total := 0.0
+	for i, d := range l.Drops {
+		key := fmt.Sprintf("game.loot.drops[%d].", i)
+		total += d.Chance
+		checks = append(checks, []check{
+			{key + "kind", slices.Contains(sim.PowerUps, d.Kind)},
+			{key + "chance", d.Chance >= 0 && total <= 1},
+			{key + "duration", d.Duration > 0 || !slices.Contains(sim.Timed, d.Kind)},
+		}...)
+	}
+	for _, check := range checks {
+		if !check.ok {
+			return fmt.Errorf("%s: out of range", check.key)
+		}
+	}
+	return nil
+}
+
+// increasing reports whether scores are all above 0 and each above the last.
+func increasing(scores []int64) bool {
+	last := int64(0)
+	for _, s := range scores {
+		if s <= last {
+			return false
+		}
+		last = s
+	}
+	return true
+}
+
+// Difficulties are the names ApplyDifficulty accepts, easiest first.
+var Difficulties = []string{"easy", "normal", "hard"}
+
+// ApplyDifficulty scales the enemies' tuning up or down from what's
+// configured, and gives a life more or less. Normal leaves it as it is.
+func (c *Config) ApplyDifficulty(name string) error {
+	t := &c.Game
+	switch name {
+	case "easy":
+		t.EnemySpeed *= 0.75
+		t.EnemyFireRate *= 0.5
+		if t.MaxEnemies > 1 {
+			t.MaxEnemies--
+		}
+		t.Lives++
+	case "normal":
+	case "hard":
+		t.EnemySpeed *= 1.25
+		t.EnemyFireRate *= 1.5
+		t.MaxEnemies += 2
+		if t.Lives > 1 {
+			t.Lives--
+		}
+	default:
+		return fmt.Errorf("unknown difficulty %q (want %s)", name, strings.Join(Difficulties, ", "))
+	}
+	return nil
+}
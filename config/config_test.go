@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func load(t *testing.T, data string) (Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return Load(path, true)
+}
+
+func TestLoadKeepsDefaultEnemies(t *testing.T) {
+	c, err := load(t, `{"game": {"max_enemies": 6}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Game.Enemies) != len(Default().Game.Enemies) {
+		t.Errorf("got %d enemy classes, want the %d defaults", len(c.Game.Enemies), len(Default().Game.Enemies))
+	}
+}
+
+func TestLoadReplacesEnemies(t *testing.T) {
+	c, err := load(t, `{"game": {"enemies": [
+		{"name": "a", "weight": 1, "health": 1, "damage": 1, "points": 1, "speed_mul": 1, "fire_rate_mul": 1, "scale_mul": 1},
+		{"name": "b", "weight": 1, "health": 2, "damage": 1, "speed_mul": 1, "fire_rate_mul": 1, "scale_mul": 1}
+	]}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Game.Enemies) != 2 {
+		t.Fatalf("got %d enemy classes, want 2", len(c.Game.Enemies))
+	}
+	// Nothing of the default class at the same index may leak in.
+	if e := c.Game.Enemies[1]; e.Points != 0 || e.SpeedMul != 1 || e.ScaleMul != 1 {
+		t.Errorf("got points %d, speed_mul %v, scale_mul %v; want 0, 1 and 1", e.Points, e.SpeedMul, e.ScaleMul)
+	}
+}
+
+func TestLoadRejectsPartialEnemy(t *testing.T) {
+	_, err := load(t, `{"game": {"enemies": [
+		{"name": "a", "weight": 1, "health": 1, "damage": 1, "speed_mul": 1, "fire_rate_mul": 1, "scale_mul": 1},
+		{"name": "b", "weight": 1, "health": 4}
+	]}}`)
+	if err == nil || !strings.Contains(err.Error(), "game.enemies[1].damage") {
+		t.Errorf("got %v, want an error naming game.enemies[1].damage", err)
+	}
+}
+
+func TestLoadReplacesDrops(t *testing.T) {
+	c, err := load(t, `{"game": {"loot": {"drops": [{"kind": "extra_life", "chance": 0.1}]}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Game.Loot.Drops) != 1 || c.Game.Loot.Drops[0].Duration != 0 {
+		t.Errorf("got drops %+v, want just the extra life, with no duration", c.Game.Loot.Drops)
+	}
+	if c.Game.Loot.Lifetime != Default().Game.Loot.Lifetime {
+		t.Errorf("got lifetime %v, want the default kept", c.Game.Loot.Lifetime)
+	}
+}
+
+func TestLoadRejectsDropWithoutDuration(t *testing.T) {
+	_, err := load(t, `{"game": {"loot": {"drops": [{"kind": "shield", "chance": 0.5}]}}}`)
+	if err == nil || !strings.Contains(err.Error(), "game.loot.drops[0].duration") {
+		t.Errorf("got %v, want an error naming game.loot.drops[0].duration", err)
+	}
+}
+
+func TestLoadRejectsNoHitFlash(t *testing.T) {
+	_, err := load(t, `{"game": {"hit_flash": 0}}`)
+	if err == nil || !strings.Contains(err.Error(), "game.hit_flash") {
+		t.Errorf("got %v, want an error naming game.hit_flash", err)
+	}
+}
+
+func TestLoadNamesBadKey(t *testing.T) {
+	for _, c := range []struct{ data, key string }{
+		{`{"world": {"width": -1}}`, "world.width"},
+		{`{"window": {"height": 0}}`, "window.height"},
+		{`{"game": {"padding": 1000}}`, "game.padding"},
+		{`{"game": {"player_speed": 0}}`, "game.player_speed"},
+		{`{"game": {"lives": 0}}`, "game.lives"},
+		{`{"game": {"extra_lives": [5000, 1000]}}`, "game.extra_lives"},
+		{`{"game": {"loot": {"radius": 0}}}`, "game.loot.radius"},
+		// A value of the wrong type is named by the decoder.
+		{`{"game": {"player_speed": "fast"}}`, "game.player_speed"},
+	} {
+		if _, err := load(t, c.data); err == nil || !strings.Contains(err.Error(), c.key) {
+			t.Errorf("%s: got %v, want an error naming %s", c.data, err, c.key)
+		}
+	}
+}
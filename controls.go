@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/ui"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+// controlsScene lists each action with its buttons. Picking one waits for the
+// next button pressed and binds the action to it.
+type controlsScene struct {
+	app       *app
+	actions   *input.Map
+	list      *ui.List
+	capturing bool
+	ui        *ui.UI
+}
+
+func newControlsScene(a *app) *controlsScene {
+	c := &controlsScene{app: a, actions: a.actions}
+	u := a.newUI()
+	c.list = u.List(c.rows(), func(int) { c.capturing = true })
+	u.Add(u.VStack(
+		u.StyledLabel(a.tr("controls.title"), titleStyle),
+		ui.NewSpace(0, 16),
+		c.list,
+		ui.NewSpace(0, 16),
+		u.Button(a.tr("menu.back"), func() { a.scenes.pop() }),
+		ui.NewSpace(0, 16),
+		u.Label(a.tr("controls.hint")),
+	), hud.TopLeft, titleMargin)
+	c.ui = u
+	return c
+}
+
+func (c *controlsScene) enter() {}
+
+// exit saves the bindings, so a rebind survives the next launch.
+func (c *controlsScene) exit() {
+	if c.app.bindingsPath == "" {
+		return
+	}
+	if err := c.actions.Bindings.Save(c.app.bindingsPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// column pads labels with spaces to the same number of runes, so what
+// follows them lines up in a fixed-width font.
+func column(labels ...string) []string {
+	width := 0
+	for _, l := range labels {
+		if n := utf8.RuneCountInString(l); n > width {
+			width = n
+		}
+	}
+	padded := make([]string, len(labels))
+	for i, l := range labels {
+		padded[i] = l + strings.Repeat(" ", width-utf8.RuneCountInString(l))
+	}
+	return padded
+}
+
+// rows is a row for each action: its name, then its buttons, or a prompt
+// for the one being rebound.
+func (c *controlsScene) rows() []string {
+	names := []string{}
+	for _, action := range input.Actions {
+		names = append(names, c.app.t("action."+action.String()))
+	}
+	names = column(names...)
+	for i, action := range input.Actions {
+		buttons := []string{}
+		for _, button := range c.actions.Bindings[action] {
+			buttons = append(buttons, button.String())
+		}
+		binding := strings.Join(buttons, ", ")
+		if c.capturing && i == c.list.Selected {
+			binding = c.app.t("controls.capture")
+		}
+		names[i] += " " + binding
+	}
+	return names
+}
+
+func (c *controlsScene) update(dt float64) {
+	if c.capturing {
+		if button, ok := c.actions.Captured(); ok {
+			c.actions.Rebind(input.Actions[c.list.Selected], button)
+			c.capturing = false
+		}
+		c.list.Items = c.rows()
+		return
+	}
+
+	if c.actions.JustPressed(input.Back) {
+		c.app.scenes.pop()
+		return
+	}
+	c.ui.Update(c.app.uiInput(), c.app.world)
+	c.list.Items = c.rows()
+}
+
+func (c *controlsScene) draw(win *pixelgl.Window) {
+	win.Clear(colornames.Mediumaquamarine)
+	c.ui.Draw(win, c.app.world)
+}
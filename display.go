@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/TheKaterTot/pixelTest/display"
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/i18n"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/ui"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+// displayScene edits a copy of the display settings. Nothing changes until
+// the player applies them, which also saves them; backing out drops them.
+type displayScene struct {
+	app         *app
+	pending     display.Settings
+	monitors    []string
+	resolutions []display.Resolution
+	resolution  *ui.Slider
+	ui          *ui.UI
+}
+
+func newDisplayScene(a *app) *displayScene {
+	return &displayScene{app: a, pending: a.display}
+}
+
+func (s *displayScene) enter() {
+	s.monitors = nil
+	for _, m := range pixelgl.Monitors() {
+		s.monitors = append(s.monitors, m.Name())
+	}
+	if s.pending.Monitor >= len(s.monitors) {
+		s.pending.Monitor = 0
+	}
+	s.listResolutions()
+	s.build()
+}
+
+func (s *displayScene) exit() {}
+
+// listResolutions lists the pending monitor's resolutions, with its desktop
+// resolution first.
+func (s *displayScene) listResolutions() {
+	s.resolutions = append([]display.Resolution{{}}, display.Resolutions(s.pending.Monitor)...)
+	if s.resolution != nil {
+		s.resolution.Max = float64(len(s.resolutions) - 1)
+		s.resolution.Value = float64(indexOf(s.resolutions, s.pending.Resolution))
+	}
+}
+
+func indexOf[T comparable](items []T, item T) int {
+	for i, it := range items {
+		if it == item {
+			return i
+		}
+	}
+	return 0
+}
+
+// choice is a slider that steps through n choices, starting at the i'th.
+func choice(u *ui.UI, text ui.Text, i, n int, onChange func(i int)) *ui.Slider {
+	return u.Slider(text, 0, float64(max(n-1, 0)), 1, float64(i), func(v float64) { onChange(int(v)) })
+}
+
+func (s *displayScene) build() {
+	a, p := s.app, &s.pending
+	u := a.newUI()
+	row := func(key string, value func() string) ui.Text {
+		return func() string { return a.t(key) + "  " + value() }
+	}
+
+	mode := choice(u, row("display.mode", func() string { return a.t("display.mode." + string(p.Mode)) }),
+		indexOf(display.Modes, p.Mode), len(display.Modes), func(i int) { p.Mode = display.Modes[i] })
+	monitor := choice(u, row("display.monitor", s.monitorName), p.Monitor, len(s.monitors), func(i int) {
+		p.Monitor = i
+		p.Resolution = display.Resolution{}
+		s.listResolutions()
+	})
+	s.resolution = choice(u, row("display.resolution", s.resolutionName),
+		indexOf(s.resolutions, p.Resolution), len(s.resolutions), func(i int) { p.Resolution = s.resolutions[i] })
+	vsync := u.Toggle(a.tr("display.vsync"), p.VSync, func(on bool) { p.VSync = on })
+	frameCap := choice(u, row("display.frame_cap", s.frameCapName),
+		indexOf(display.FrameCaps, p.FrameCap), len(display.FrameCaps), func(i int) { p.FrameCap = display.FrameCaps[i] })
+
+	settings := u.VStack(mode, monitor, s.resolution, vsync, frameCap)
+	settings.Stretch = true
+	buttons := u.HStack(u.Button(a.tr("display.apply"), s.apply), u.Button(a.tr("menu.back"), func() { a.scenes.pop() }))
+	u.Add(u.VStack(
+		u.StyledLabel(a.tr("display.title"), titleStyle),
+		ui.NewSpace(0, 16),
+		settings,
+		ui.NewSpace(0, 16),
+		buttons,
+		ui.NewSpace(0, 16),
+		u.Label(a.tr("display.hint")),
+	), hud.TopLeft, titleMargin)
+	s.ui = u
+}
+
+func (s *displayScene) monitorName() string {
+	if s.pending.Monitor < len(s.monitors) {
+		return s.monitors[s.pending.Monitor]
+	}
+	return s.app.t("display.primary")
+}
+
+func (s *displayScene) resolutionName() string {
+	p, c := s.pending, s.app.catalog
+	resolution := p.Resolution.String()
+	if p.Resolution == (display.Resolution{}) {
+		resolution = c.T("display.desktop", nil)
+	}
+	if p.Mode != display.Fullscreen {
+		resolution = c.T("display.fullscreen_only", i18n.Args{"resolution": resolution})
+	}
+	return resolution
+}
+
+func (s *displayScene) frameCapName() string {
+	if s.pending.FrameCap > 0 {
+		return s.app.catalog.N("display.fps", s.pending.FrameCap, nil)
+	}
+	return s.app.t("common.off")
+}
+
+func (s *displayScene) apply() {
+	s.app.display = s.pending
+	s.pending.Apply(s.app.win)
+	if s.app.displayPath == "" {
+		return
+	}
+	if err := s.pending.Save(s.app.displayPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+func (s *displayScene) update(dt float64) {
+	if s.app.actions.JustPressed(input.Back) {
+		s.app.scenes.pop()
+		return
+	}
+	s.ui.Update(s.app.uiInput(), s.app.world)
+}
+
+func (s *displayScene) draw(win *pixelgl.Window) {
+	win.Clear(colornames.Mediumaquamarine)
+	s.ui.Draw(win, s.app.world)
+}
@@ -0,0 +1,201 @@
+// Package display holds the player's window settings: windowed, borderless
+// or fullscreen, on which monitor and at what resolution, with or without
+// VSync and a frame cap. They're kept between runs, like the bindings.
+package display
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/faiface/mainthread"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+type Mode string
+
+const (
+	Windowed Mode = "windowed"
+	// Borderless covers the monitor at its desktop resolution, without
+	// switching video modes, so alt-tabbing away is instant.
+	Borderless Mode = "borderless"
+	// Fullscreen takes the monitor over, at Settings.Resolution.
+	Fullscreen Mode = "fullscreen"
+)
+
+// Modes lists every Mode in the order the settings screen cycles them.
+var Modes = []Mode{Windowed, Borderless, Fullscreen}
+
+// Resolution is a fullscreen video mode's size. The zero Resolution means the
+// monitor's desktop resolution.
+type Resolution struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+func (r Resolution) String() string {
+	if r == (Resolution{}) {
+		return "desktop"
+	}
+	return fmt.Sprintf("%dx%d", r.Width, r.Height)
+}
+
+// FrameCaps are the frame caps the settings screen offers. Zero is uncapped.
+var FrameCaps = []int{0, 30, 60, 120, 144, 240}
+
+type Settings struct {
+	Mode Mode `json:"mode"`
+	// Monitor indexes pixelgl.Monitors(). One that's gone falls back to the
+	// primary monitor.
+	Monitor    int        `json:"monitor"`
+	Resolution Resolution `json:"resolution"`
+	VSync      bool       `json:"vsync"`
+	// FrameCap is the most frames drawn per second, zero for no limit.
+	FrameCap int `json:"frame_cap"`
+}
+
+func Default() Settings {
+	return Settings{Mode: Windowed, VSync: true}
+}
+
+// Path is where the display settings are kept between runs.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pixelTest", "display.json"), nil
+}
+
+// Load reads settings from path. A missing file gives the defaults.
+func Load(path string) (Settings, error) {
+	s := Default()
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Default(), fmt.Errorf("%s: %v", path, err)
+	}
+	if err := s.validate(); err != nil {
+		return Default(), fmt.Errorf("%s: %v", path, err)
+	}
+	return s, nil
+}
+
+func (s Settings) validate() error {
+	known := false
+	for _, m := range Modes {
+		known = known || s.Mode == m
+	}
+	switch {
+	case !known:
+		return fmt.Errorf("mode: unknown mode %q", s.Mode)
+	case s.Monitor < 0:
+		return errors.New("monitor: out of range")
+	case s.Resolution.Width < 0 || s.Resolution.Height < 0:
+		return errors.New("resolution: out of range")
+	case s.FrameCap < 0:
+		return errors.New("frame_cap: out of range")
+	}
+	return nil
+}
+
+// Save writes s to path, replacing the old file only once the new one is
+// complete.
+func (s Settings) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// monitor is the monitor s asks for, or the primary one if it's gone.
+func (s Settings) monitor() *pixelgl.Monitor {
+	monitors := pixelgl.Monitors()
+	if s.Monitor < len(monitors) {
+		return monitors[s.Monitor]
+	}
+	return pixelgl.PrimaryMonitor()
+}
+
+// Resolutions lists the sizes the numbered monitor's video modes come in,
+// smallest first. pixelgl doesn't expose video modes, so this asks glfw
+// directly; its monitors come in the same order as pixelgl.Monitors().
+func Resolutions(monitor int) []Resolution {
+	seen := map[Resolution]bool{}
+	mainthread.Call(func() {
+		monitors := glfw.GetMonitors()
+		if monitor >= len(monitors) {
+			return
+		}
+		for _, mode := range monitors[monitor].GetVideoModes() {
+			seen[Resolution{Width: mode.Width, Height: mode.Height}] = true
+		}
+	})
+
+	resolutions := []Resolution{}
+	for r := range seen {
+		resolutions = append(resolutions, r)
+	}
+	sort.Slice(resolutions, func(i, j int) bool {
+		a, b := resolutions[i], resolutions[j]
+		if a.Width != b.Width {
+			return a.Width < b.Width
+		}
+		return a.Height < b.Height
+	})
+	return resolutions
+}
+
+// Apply puts win into the mode s describes. A window taken out of
+// fullscreen gets back the size and place it had before.
+func (s Settings) Apply(win *pixelgl.Window) {
+	// pixelgl remembers the window's size on the way into fullscreen, so
+	// going straight from one fullscreen mode to another would remember the
+	// fullscreen size. Leaving fullscreen first keeps the windowed one.
+	if win.Monitor() != nil {
+		win.SetMonitor(nil)
+	}
+
+	switch s.Mode {
+	case Borderless:
+		// Fullscreen at the desktop video mode is glfw's windowed
+		// fullscreen: no mode switch and no decorations.
+		win.SetMonitor(s.monitor())
+	case Fullscreen:
+		win.SetMonitor(s.monitor())
+		if s.Resolution != (Resolution{}) {
+			win.SetBounds(pixel.R(0, 0, float64(s.Resolution.Width), float64(s.Resolution.Height)))
+		}
+	}
+	win.SetVSync(s.VSync)
+}
+
+// FrameTime is the shortest a frame may take under the frame cap, or zero.
+func (s Settings) FrameTime() time.Duration {
+	if s.FrameCap <= 0 {
+		return 0
+	}
+	return time.Second / time.Duration(s.FrameCap)
+}
@@ -0,0 +1,74 @@
+package ecs
+
+import "sort"
+
+// Store holds one type of component. Iteration is in entity order, so running
+// the same systems over the same world always visits entities the same way.
+type Store[T any] struct {
+	items map[Entity]*T
+	ids   []Entity
+}
+
+func NewStore[T any](w *World) *Store[T] {
+	s := &Store[T]{items: map[Entity]*T{}}
+	w.stores = append(w.stores, s)
+	return s
+}
+
+// Add gives e the component v, replacing any it already had, and returns a
+// pointer to the stored copy.
+func (s *Store[T]) Add(e Entity, v T) *T {
+	if _, ok := s.items[e]; !ok {
+		i := sort.Search(len(s.ids), func(i int) bool { return s.ids[i] >= e })
+		s.ids = append(s.ids, 0)
+		copy(s.ids[i+1:], s.ids[i:])
+		s.ids[i] = e
+	}
+	p := &v
+	s.items[e] = p
+	return p
+}
+
+// Get returns e's component, or nil if it has none.
+func (s *Store[T]) Get(e Entity) *T {
+	return s.items[e]
+}
+
+func (s *Store[T]) Has(e Entity) bool {
+	_, ok := s.items[e]
+	return ok
+}
+
+func (s *Store[T]) Remove(e Entity) {
+	if _, ok := s.items[e]; !ok {
+		return
+	}
+	delete(s.items, e)
+	i := sort.Search(len(s.ids), func(i int) bool { return s.ids[i] >= e })
+	s.ids = append(s.ids[:i], s.ids[i+1:]...)
+}
+
+func (s *Store[T]) Len() int {
+	return len(s.ids)
+}
+
+// Each calls fn for every entity with this component. Components added
+// during the loop aren't visited and ones removed are skipped.
+func (s *Store[T]) Each(fn func(e Entity, v *T)) {
+	ids := append([]Entity(nil), s.ids...)
+	for _, e := range ids {
+		if v, ok := s.items[e]; ok {
+			fn(e, v)
+		}
+	}
+}
+
+// CopyFrom replaces s's contents with copies of src's components.
+func (s *Store[T]) CopyFrom(src *Store[T]) {
+	s.items = map[Entity]*T{}
+	for e, v := range src.items {
+		c := *v
+		s.items[e] = &c
+	}
+	s.ids = append([]Entity(nil), src.ids...)
+}
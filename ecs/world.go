@@ -0,0 +1,140 @@
+// Package ecs is a small entity-component-system. Entities are bare IDs,
+// components live in typed Stores keyed by entity, and systems are functions
+// the World runs in the order they were added.
+package ecs
+
+import "sort"
+
+type Entity uint64
+
+// Phase groups systems that run together. Update systems advance the game by
+// dt seconds; Draw systems are given the fraction of a tick to interpolate by
+// instead.
+type Phase int
+
+const (
+	Update Phase = iota
+	Draw
+)
+
+type System func(dt float64)
+
+type namedSystem struct {
+	name   string
+	system System
+}
+
+type remover interface {
+	Remove(e Entity)
+}
+
+type World struct {
+	next    Entity
+	alive   map[Entity]bool
+	dead    []Entity
+	stores  []remover
+	systems map[Phase][]namedSystem
+}
+
+func NewWorld() *World {
+	return &World{
+		next:    1,
+		alive:   map[Entity]bool{},
+		systems: map[Phase][]namedSystem{},
+	}
+}
+
+func (w *World) Spawn() Entity {
+	e := w.next
+	w.next++
+	w.alive[e] = true
+	return e
+}
+
+// Despawn marks e for removal. It keeps its components until the running
+// system returns, so a system can despawn entities while iterating over them.
+func (w *World) Despawn(e Entity) {
+	if w.alive[e] {
+		w.alive[e] = false
+		w.dead = append(w.dead, e)
+	}
+}
+
+func (w *World) Alive(e Entity) bool {
+	return w.alive[e]
+}
+
+// Flush removes everything despawned since the last Flush from every store.
+func (w *World) Flush() {
+	for _, e := range w.dead {
+		for _, s := range w.stores {
+			s.Remove(e)
+		}
+		delete(w.alive, e)
+	}
+	w.dead = w.dead[:0]
+}
+
+// CopyFrom makes w's set of entities the same as src's. Components aren't
+// copied; each Store has its own CopyFrom for that.
+func (w *World) CopyFrom(src *World) {
+	src.Flush()
+	w.next = src.next
+	w.alive = map[Entity]bool{}
+	for e, alive := range src.alive {
+		w.alive[e] = alive
+	}
+	w.dead = nil
+}
+
+// Next is the ID the next Spawn will hand out.
+func (w *World) Next() Entity {
+	return w.next
+}
+
+// Entities lists the living entities in ID order.
+func (w *World) Entities() []Entity {
+	ids := []Entity{}
+	for e, alive := range w.alive {
+		if alive {
+			ids = append(ids, e)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Restore sets the world's entities to ids, with next as the next ID to hand
+// out, for rebuilding a saved world. Components go back into the stores
+// separately.
+func (w *World) Restore(ids []Entity, next Entity) {
+	w.alive = map[Entity]bool{}
+	for _, e := range ids {
+		w.alive[e] = true
+		if e >= next {
+			next = e + 1
+		}
+	}
+	w.next = next
+	w.dead = nil
+}
+
+func (w *World) AddSystem(phase Phase, name string, system System) {
+	w.systems[phase] = append(w.systems[phase], namedSystem{name, system})
+}
+
+// Systems lists the names of a phase's systems in the order they run.
+func (w *World) Systems(phase Phase) []string {
+	names := []string{}
+	for _, s := range w.systems[phase] {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+func (w *World) Run(phase Phase, dt float64) {
+	for _, s := range w.systems[phase] {
+		s.system(dt)
+		w.Flush()
+	}
+}
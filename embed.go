@@ -0,0 +1,36 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+
+	"github.com/TheKaterTot/pixelTest/assets"
+)
+
+//go:embed images
+var bundle embed.FS
+
+// assetsEnv names a directory of loose files to use in place of the bundled
+// ones, for when --assets isn't given.
+const assetsEnv = "PIXELTEST_ASSETS"
+
+// assetFS is the bundled images directory, with dir laid over it when there
+// is one. An empty dir falls back to $PIXELTEST_ASSETS.
+func assetFS(dir string) (fs.FS, error) {
+	images, err := fs.Sub(bundle, "images")
+	if err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		dir = os.Getenv(assetsEnv)
+	}
+	if dir == "" {
+		return images, nil
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	return assets.Overlay(os.DirFS(dir), images), nil
+}
@@ -1,233 +1,245 @@
 package main
 
 import (
-	"fmt"
-	"math/rand"
-
+	"image/color"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/i18n"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/render"
+	"github.com/TheKaterTot/pixelTest/replay"
+	"github.com/TheKaterTot/pixelTest/sim"
 	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
 	"github.com/faiface/pixel/pixelgl"
-	"github.com/faiface/pixel/text"
 	"golang.org/x/image/colornames"
-	"golang.org/x/image/font/basicfont"
 )
 
-type gameState struct {
-	enemies  []*entity
-	missiles []*entity
-}
-
 type game struct {
-	score        int64
-	player       *entity
-	enemyMissles []*entity
-	current      gameState
-	next         gameState
-	running      bool
-}
-
-func newGameState() gameState {
-	return gameState{
-		[]*entity{},
-		[]*entity{},
-	}
-}
-
-func newGame() *game {
-	player, _ := placenewSprite()
-
-	return &game{
-		score:        int64(0),
-		player:       player,
-		enemyMissles: []*entity{},
-		current:      newGameState(),
-		next:         newGameState(),
-		running:      true,
-	}
-}
-
-func (g *game) swapStates() {
-	g.current = g.next
-	g.next = newGameState()
-}
-
-func (g *game) makeEnemies(win *pixelgl.Window, max int) {
-	number := max - len(g.current.enemies)
-
-	for i := 0; i < number; i++ {
-		enemy, err := placeNewEnemy(win)
-		if err != nil {
-			panic(err)
+	world   *sim.Game
+	sprites map[string]*pixel.Sprite
+	queue   *render.Queue
+	shapes  *imdraw.IMDraw
+	replay  *replay.Replay
+	score   *hud.Label
+	lives   *hud.Label
+	effects *hud.Label
+	fire    bool
+}
+
+func newGame(bounds pixel.Rect, fonts *hud.Fonts, sprites map[string]*pixel.Sprite, defs map[string]sim.SpriteDef, t sim.Tuning, seed int64) *game {
+	g := newGameView(sim.New(bounds, defs, t, seed), fonts, sprites)
+	g.replay = &replay.Replay{Seed: seed, Bounds: bounds, Tuning: t}
+	return g
+}
+
+// newGameView draws a world that something else steps, like a replay.
+func newGameView(world *sim.Game, fonts *hud.Fonts, sprites map[string]*pixel.Sprite) *game {
+	g := &game{
+		sprites: sprites,
+		queue:   render.NewQueue(),
+		shapes:  imdraw.New(nil),
+		score:   fonts.Label(hudStyle, hud.TopLeft, pixel.V(hudMargin, hudMargin)),
+		lives:   fonts.Label(hudStyle, hud.TopRight, pixel.V(hudMargin, hudMargin)),
+		effects: fonts.Label(effectsStyle, hud.TopRight, pixel.V(hudMargin, hudMargin+hudStyle.Size*1.5)),
+	}
+	g.setWorld(world)
+	return g
+}
+
+func (g *game) setWorld(world *sim.Game) {
+	g.world = world
+	g.world.World.AddSystem(ecs.Draw, "render", g.renderSystem)
+}
+
+func (g *game) displayHUD(win *pixelgl.Window, world pixel.Rect, c *i18n.Catalog) {
+	g.score.SetText(c.T("hud.score", i18n.Args{"score": g.world.Score}))
+	g.score.Draw(win, world)
+	g.lives.SetText(c.N("hud.lives", g.world.Lives, nil))
+	g.lives.Draw(win, world)
+	g.effects.SetText(effectsText(g.world, c))
+	g.effects.Draw(win, world)
+}
+
+// effectsText lists the player's running power-ups with the time each has
+// left, one to a line.
+func effectsText(world *sim.Game, c *i18n.Catalog) string {
+	fx := world.Effects.Get(world.Player)
+	if fx == nil {
+		return ""
+	}
+	lines := []string{}
+	for _, kind := range sim.Timed {
+		if left := fx.Left(kind); left > 0 {
+			seconds := strconv.FormatFloat(left, 'f', 1, 64)
+			lines = append(lines, c.T("hud.effect", i18n.Args{"name": c.T("powerup."+string(kind), nil), "seconds": seconds}))
 		}
-		g.current.enemies = append(g.current.enemies, enemy)
 	}
+	return strings.Join(lines, "\n")
 }
 
-func (g *game) updateEnemies() {
-	enemySpeed := 1.5
-
-	for _, enemy := range g.current.enemies {
-		enemy.Pos.X -= enemySpeed
-		roll := rand.Int63n(10000)
-		if roll <= 25 {
-			missile, err := newMissile(enemy.Pos)
-			if err != nil {
-				panic(err)
-			}
-			g.enemyMissles = append(g.enemyMissles, missile)
-		}
-	}
-}
-
-func (g *game) updateMissiles() {
-	missileSpeed := 3.5
-
-	for _, missile := range g.current.missiles {
-		missile.Pos.X += missileSpeed
-	}
-
-	for _, missile := range g.enemyMissles {
-		missile.Pos.X -= missileSpeed * 1.5
-	}
-}
+// blinkRate is how many times a second an invulnerable entity blinks.
+const blinkRate = 8
 
-func (g *game) gameStart(win *pixelgl.Window) {
-	win.Clear(colornames.Mediumaquamarine)
-	basicAtlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
-	basicTxt := text.New(pixel.V(100, 500), basicAtlas)
-	basicTxt.Color = colornames.Black
-	fmt.Fprintln(basicTxt, "Pirates have arrived in your harbor.")
-	fmt.Fprintln(basicTxt, "Keep out enemy ships and avoid missiles.")
-	fmt.Fprintln(basicTxt, "Press Enter to Start")
-	basicTxt.Draw(win, pixel.IM.Scaled(basicTxt.Orig, 3))
-	win.Update()
-}
+// flashMask tints a ship that has just been hit.
+var flashMask = pixel.RGB(1, 0.25, 0.25)
 
-func (g *game) gameOver(win *pixelgl.Window) {
-	win.Clear(colornames.Black)
-	basicAtlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
-	basicTxt := text.New(pixel.V(100, 500), basicAtlas)
-	fmt.Fprintln(basicTxt, "GAME OVER")
-	fmt.Fprintln(basicTxt, "You have failed your people.")
-	fmt.Fprintln(basicTxt, "Press Enter to Start Again")
-	basicTxt.Draw(win, pixel.IM.Scaled(basicTxt.Orig, 4))
-	win.Update()
-}
+// Health bars sit healthGap above a damaged ship, as wide as it and
+// healthHeight tall. They turn healthLow's color at a third of full.
+const (
+	healthGap    = 4
+	healthHeight = 5
+)
 
-func (g *game) displayScore(win *pixelgl.Window) {
-	txtvec := getTextCoordinates(win)
+var (
+	healthTrack = pixel.RGBA{A: 0.5}
+	healthFill  = colornames.Limegreen
+	healthLow   = colornames.Orangered
+)
 
-	basicAtlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
-	basicTxt := text.New(txtvec, basicAtlas)
-	basicTxt.Color = colornames.Black
-	fmt.Fprintf(basicTxt, "Score: %d", g.score)
-	basicTxt.Draw(win, pixel.IM.Scaled(basicTxt.Orig, 2))
-}
+// Pickups are drawn as rings pickupRing wide, in their kind's color, and
+// the player's shield as a bubble shieldMargin out from its ship.
+const (
+	pickupRing   = 4
+	shieldMargin = 10
+)
 
-func (g *game) checkPlayer() {
-	for _, enemy := range g.current.enemies {
-		if overlap(g.player, enemy) {
-			g.running = false
-			break
-		}
+var (
+	pickupColors = map[sim.PowerUp]color.Color{
+		sim.Spread:    colornames.Gold,
+		sim.RapidFire: colornames.Orangered,
+		sim.Shield:    colornames.Deepskyblue,
+		sim.ExtraLife: colornames.Hotpink,
 	}
-	for _, missile := range g.enemyMissles {
-		if overlap(g.player, missile) {
-			g.running = false
-			break
-		}
-	}
-}
+	pickupCore  = colornames.White
+	shieldColor = pixel.RGBA{R: 0.5, G: 0.85, B: 1, A: 0.35}
+)
 
-func (g *game) filterDeadEnemies() ([]*entity, int64) {
-	enemies := []*entity{}
-	hits := int64(0)
-	for _, enemy := range g.current.enemies {
-		if !isEnemyOffWorld(enemy.Pos.X) && !anyOverlap(enemy, g.current.missiles) {
-			enemies = append(enemies, enemy)
+// blinking reports whether something with left seconds to go, and warning
+// seconds of them to flash for, is on the undrawn half of a blink.
+func blinking(left, warning float64) bool {
+	return left < warning && int(left*2*blinkRate)%2 == 1
+}
+
+// renderSystem queues every entity's sprite alpha of the way between its
+// last two positions, and draws a health bar over each damaged enemy, the
+// pickups, and the player's shield.
+func (g *game) renderSystem(alpha float64) {
+	g.shapes.Clear()
+	g.renderPickups(alpha)
+	g.world.Sprites.Each(func(e ecs.Entity, sprite *sim.Sprite) {
+		// Invulnerable entities spend every other blink undrawn.
+		if inv := g.world.Invulnerables.Get(e); inv != nil && int(inv.Remaining*2*blinkRate)%2 == 1 {
+			return
 		}
-		if anyOverlap(enemy, g.current.missiles) {
-			hits++
+		pos := g.world.Positions.Get(e).Lerp(alpha)
+		m := pixel.IM.Scaled(pixel.ZV, sprite.Scale).Moved(pos)
+		if g.world.Flashes.Has(e) {
+			g.queue.DrawColorMask(g.sprites[sprite.Name], m, flashMask)
+		} else {
+			g.queue.Draw(g.sprites[sprite.Name], m)
 		}
-	}
-	return enemies, hits
-}
-
-func (g *game) filterDeadMissiles() []*entity {
-	missiles := []*entity{}
-	for _, missile := range g.current.missiles {
-		if !isMissileOffWorld(missile.Pos.X) && !anyOverlap(missile, g.current.enemies) {
-			missiles = append(missiles, missile)
+		size := sprite.Frame.Size().Scaled(sprite.Scale)
+		// Only enemies get a bar.
+		if h := g.world.Healths.Get(e); h != nil && h.Current < h.Max && e != g.world.Player {
+			g.healthBar(size, pos, h)
 		}
-	}
-	return missiles
-}
-
-func (g *game) checkHarbor() {
-	for _, enemy := range g.current.enemies {
-		if isEnemyOffWorld(enemy.Pos.X) {
-			g.running = false
-			break
+		if fx := g.world.Effects.Get(e); fx != nil && fx.Shield > 0 && !blinking(fx.Shield, g.world.Tuning.Loot.Warning) {
+			g.shapes.Color = shieldColor
+			g.shapes.Push(pos)
+			g.shapes.Circle(size.Len()/2+shieldMargin, 0)
 		}
-	}
+	})
 }
 
-func (g *game) draw(win *pixelgl.Window) {
+// renderPickups draws each pickup as a ring in its kind's color, flashing as
+// it's about to go.
+func (g *game) renderPickups(alpha float64) {
+	loot := g.world.Tuning.Loot
+	g.world.Pickups.Each(func(e ecs.Entity, p *sim.Pickup) {
+		if l := g.world.Lifetimes.Get(e); l != nil && blinking(l.Remaining, loot.Warning) {
+			return
+		}
+		pos := g.world.Positions.Get(e).Lerp(alpha)
+		g.shapes.Color = pickupCore
+		g.shapes.Push(pos)
+		g.shapes.Circle(loot.Radius, 0)
+		g.shapes.Color = pickupColors[p.Kind]
+		g.shapes.Push(pos)
+		g.shapes.Circle(loot.Radius, pickupRing)
+	})
+}
+
+// healthBar draws h over a ship of size at pos.
+func (g *game) healthBar(size, pos pixel.Vec, h *sim.Health) {
+	corner := pos.Add(pixel.V(-size.X/2, size.Y/2+healthGap))
+	full := float64(h.Current) / float64(h.Max)
+	g.shapes.Color = healthTrack
+	g.shapes.Push(corner, corner.Add(pixel.V(size.X, healthHeight)))
+	g.shapes.Rectangle(0)
+	g.shapes.Color = healthFill
+	if full <= 0.34 {
+		g.shapes.Color = healthLow
+	}
+	g.shapes.Push(corner, corner.Add(pixel.V(size.X*full, healthHeight)))
+	g.shapes.Rectangle(0)
+}
+
+func (g *game) draw(win *pixelgl.Window, alpha float64) {
 	win.Clear(colornames.Cornflowerblue)
-	g.player.Sprite.Draw(win, pixel.IM.Scaled(pixel.ZV, g.player.Scale).Moved(g.player.Pos))
-	for _, enemy := range g.current.enemies {
-		enemy.Sprite.Draw(win, pixel.IM.Scaled(pixel.ZV, enemy.Scale).Moved(enemy.Pos))
-	}
-	for _, missile := range g.current.missiles {
-		missile.Sprite.Draw(win, pixel.IM.Scaled(pixel.ZV, missile.Scale).Moved(missile.Pos))
-	}
-	for _, missile := range g.enemyMissles {
-		missile.Sprite.Draw(win, pixel.IM.Scaled(pixel.ZV, missile.Scale).Moved(missile.Pos))
+	g.world.World.Run(ecs.Draw, alpha)
+	g.queue.Flush(win)
+	g.shapes.Draw(win)
+}
+
+// advance runs as many fixed ticks as fit in the accumulated frame time and
+// returns the leftover, which the caller uses to interpolate the next draw.
+func (g *game) advance(actions *input.Map, acc float64) float64 {
+	in := g.input(actions)
+	for acc >= sim.Tick && g.world.Running {
+		if g.replay != nil {
+			g.replay.Record(in)
+		}
+		g.world.Step(in)
+		in.Fire = false
+		g.fire = false
+		acc -= sim.Tick
 	}
+	return acc
 }
 
-func (g *game) update(win *pixelgl.Window) {
-	g.checkPlayer()
-	g.checkHarbor()
-	var hits int64
-	g.next.enemies, hits = g.filterDeadEnemies()
-	g.next.missiles = g.filterDeadMissiles()
-	g.score += hits
-
-	g.swapStates()
-	g.makeEnemies(win, 4)
-	g.updateEnemies()
-	g.updateMissiles()
-
-	g.displayScore(win)
-	win.Update()
-}
-
-func (g *game) input(win *pixelgl.Window) {
-	win.SetClosed(win.JustPressed(pixelgl.KeyEscape))
-
-	speed := 3.0
-	ctrl := pixel.ZV
+func (g *game) input(actions *input.Map) sim.Input {
+	// A press can land on a frame that runs no ticks, so hold on to it until
+	// one does.
+	g.fire = g.fire || actions.JustPressed(input.Fire)
 
-	if win.Pressed(pixelgl.KeyRight) && g.player.Pos.X < (win.Bounds().W()-padding) {
-		ctrl.X += speed
-	}
-	if win.Pressed(pixelgl.KeyLeft) && g.player.Pos.X > padding {
-		ctrl.X -= speed
+	return sim.Input{
+		Left:   actions.Pressed(input.MoveLeft),
+		Right:  actions.Pressed(input.MoveRight),
+		Up:     actions.Pressed(input.MoveUp),
+		Down:   actions.Pressed(input.MoveDown),
+		Fire:   g.fire,
+		Firing: actions.Pressed(input.Fire),
 	}
+}
 
-	if win.Pressed(pixelgl.KeyUp) && g.player.Pos.Y < (win.Bounds().H()-padding) {
-		ctrl.Y += speed
+// saveReplay writes the game so far to the replay directory, named for when
+// it was saved. Games resumed from a save aren't recorded, since their seed
+// alone can't reproduce them.
+func (g *game) saveReplay() error {
+	if g.replay == nil {
+		return nil
 	}
-
-	if win.Pressed(pixelgl.KeyDown) && g.player.Pos.Y > padding {
-		ctrl.Y -= speed
+	dir, err := replay.Dir()
+	if err != nil {
+		return err
 	}
-
-	g.player.Pos = ctrl.Add(g.player.Pos)
-
-	if win.JustPressed(pixelgl.KeySpace) {
-		missile, _ := playerFire(g.player)
-		g.current.missiles = append(g.current.missiles, missile)
-	}
-
+	g.replay.Score = g.world.Score
+	name := time.Now().Format("20060102-150405") + ".pxr"
+	return g.replay.Save(filepath.Join(dir, name))
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/replay"
+	"github.com/TheKaterTot/pixelTest/sim"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+var testDefs = map[string]sim.SpriteDef{
+	sim.PlayerSprite:  {Frame: pixel.R(0, 0, 800, 600)},
+	sim.EnemySprite:   {Frame: pixel.R(0, 0, 800, 600)},
+	sim.MissileSprite: {Frame: pixel.R(0, 0, 600, 300)},
+}
+
+func TestInputFromActions(t *testing.T) {
+	src := input.NewFake()
+	actions := input.New(src, input.DefaultBindings())
+	g := &game{}
+
+	src.Press(pixelgl.KeyRight)
+	src.Press(pixelgl.KeySpace)
+	in := g.input(actions)
+	if want := (sim.Input{Right: true, Fire: true, Firing: true}); in != want {
+		t.Errorf("input = %+v, want %+v", in, want)
+	}
+
+	// A frame that ran no ticks keeps the press for the next one.
+	src.Update()
+	if in := g.input(actions); !in.Fire {
+		t.Error("a fire press was dropped before a tick used it")
+	}
+	// Once a tick has used it, holding the button only keeps firing.
+	g.fire = false
+	if in := g.input(actions); in.Fire || !in.Firing {
+		t.Errorf("held fire gave %+v, want Firing alone", in)
+	}
+}
+
+// TestActionsReplay plays a game from button presses and checks the
+// recording plays it back the same.
+func TestActionsReplay(t *testing.T) {
+	src := input.NewFake()
+	actions := input.New(src, input.DefaultBindings())
+	g := &game{}
+	r := &replay.Replay{Seed: 5, Bounds: pixel.R(0, 0, 1024, 768), Tuning: sim.DefaultTuning()}
+	world := sim.New(r.Bounds, testDefs, r.Tuning, r.Seed)
+
+	held := []pixelgl.Button{pixelgl.KeyUp, pixelgl.KeySpace, pixelgl.KeyDown, pixelgl.KeySpace}
+	for tick := 0; tick < 60*60 && world.Running; tick++ {
+		// Swap the held button every half second.
+		if tick%30 == 0 {
+			for _, b := range held {
+				src.Release(b)
+			}
+			src.Press(held[tick/30%len(held)])
+		}
+		in := g.input(actions)
+		r.Record(in)
+		world.Step(in)
+		g.fire = false
+		src.Update()
+	}
+	r.Score = world.Score
+
+	buf := &bytes.Buffer{}
+	if err := r.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := replay.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	played, err := got.Play(testDefs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if played.Score != world.Score {
+		t.Errorf("playback scored %d, the game %d", played.Score, world.Score)
+	}
+}
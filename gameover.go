@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"time"
+
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/scores"
+	"github.com/TheKaterTot/pixelTest/ui"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+// gameOverScene shows the final score and the high scores. A score that
+// makes the table first asks for the player's initials.
+type gameOverScene struct {
+	app      *app
+	game     *game
+	entering bool
+	rank     int
+	ui       *ui.UI
+}
+
+func newGameOverScene(a *app, g *game) *gameOverScene {
+	s := &gameOverScene{app: a, game: g, entering: a.scores.Qualifies(g.world.Score), rank: -1}
+	s.build()
+	return s
+}
+
+// build lays the screen out for asking for initials, or once that's done for
+// showing the table.
+func (s *gameOverScene) build() {
+	a := s.app
+	u := a.newUI()
+	// Light on the black background.
+	u.Theme.Text.Color = colornames.White
+	u.Theme.Track = color.RGBA{R: 0x40, G: 0x40, B: 0x40, A: 0x40}
+	u.Theme.Accent = colornames.White
+	tableStyle := scoresStyle
+	tableStyle.Color = colornames.White
+	tableStyle.Align = hud.AlignCenter
+
+	banner := u.VStack(
+		u.StyledLabel(a.tr("gameover.title"), bannerStyle),
+		u.StyledLabel(a.tr("gameover.message"), bannerStyle),
+		u.StyledLabel(func() string { return a.catalog.N("gameover.score", int(s.game.world.Score), nil) }, bannerStyle),
+	)
+	banner.Align = ui.Middle
+	if s.entering {
+		field := u.TextField(scores.MaxInitials, s.submit)
+		field.Filter = scores.CleanInitials
+		banner.Children = append(banner.Children, u.StyledLabel(a.tr("gameover.new_high"), bannerStyle), field)
+		u.Add(u.StyledLabel(a.tr("gameover.initials_hint"), tableStyle), hud.Bottom, pixel.V(0, 60))
+		u.Focus(field)
+	} else {
+		banner.Children = append(banner.Children, u.StyledLabel(a.tr("gameover.again"), bannerStyle))
+		u.Add(u.StyledLabel(scoresText(a, &s.rank), tableStyle), hud.Bottom, pixel.V(0, 60))
+	}
+	u.Add(banner, hud.Top, pixel.V(0, 60))
+	s.ui = u
+}
+
+// submit puts the initials on the table.
+func (s *gameOverScene) submit(initials string) {
+	if initials == "" {
+		return
+	}
+	s.entering = false
+	s.rank = s.app.scores.Add(scores.Entry{
+		Initials: initials,
+		Score:    s.game.world.Score,
+		Date:     time.Now(),
+		Mode:     s.app.mode,
+	})
+	s.build()
+	if s.app.scoresPath == "" {
+		return
+	}
+	if err := s.app.scores.Save(s.app.scoresPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+func (s *gameOverScene) enter() {}
+
+func (s *gameOverScene) exit() {}
+
+func (s *gameOverScene) update(dt float64) {
+	// Checked first, so the Confirm that enters the initials doesn't also
+	// start the next game.
+	if !s.entering && s.app.actions.JustPressed(input.Confirm) {
+		s.app.scenes.replace(newPlayingScene(s.app))
+		return
+	}
+	s.ui.Update(s.app.uiInput(), s.app.world)
+}
+
+func (s *gameOverScene) draw(win *pixelgl.Window) {
+	win.Clear(colornames.Black)
+	s.ui.Draw(win, s.app.world)
+}
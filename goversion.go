@@ -0,0 +1,7 @@
+//go:build !go1.21
+
+package main
+
+// The game needs Go 1.21 or later: it uses generics, the slices package and
+// the min and max builtins. Older compilers stop here, on a name that says so.
+var _ = pixelTest_needs_Go_1_21_or_later
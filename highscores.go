@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheKaterTot/pixelTest/i18n"
+	"github.com/TheKaterTot/pixelTest/scores"
+	"github.com/TheKaterTot/pixelTest/ui"
+)
+
+// writeScores writes the high-score table, marking the entry at highlight,
+// which is -1 for none.
+func writeScores(w io.Writer, a *app, highlight int) {
+	fmt.Fprintln(w, a.t("scores.title"))
+	if len(a.scores.Entries) == 0 {
+		fmt.Fprintln(w, a.t("scores.none"))
+		return
+	}
+	for i, e := range a.scores.Entries {
+		marker := "  "
+		if i == highlight {
+			marker = "> "
+		}
+		initials := e.Initials + strings.Repeat(" ", scores.MaxInitials-len([]rune(e.Initials)))
+		fmt.Fprintln(w, marker+a.catalog.T("scores.row", i18n.Args{
+			"rank":     fmt.Sprintf("%2d", i+1),
+			"initials": initials,
+			"score":    fmt.Sprintf("%6d", e.Score),
+			"date":     e.Date.Format("2006-01-02"),
+			"mode":     a.t("mode." + e.Mode),
+		}))
+	}
+}
+
+// scoresText is the high-score table as a widget's text, marking the entry
+// at *highlight when highlight isn't nil.
+func scoresText(a *app, highlight *int) ui.Text {
+	return func() string {
+		at := -1
+		if highlight != nil {
+			at = *highlight
+		}
+		b := &strings.Builder{}
+		writeScores(b, a, at)
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+}
@@ -0,0 +1,117 @@
+package hud
+
+import (
+	"image"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// outlineFace is a font.Face for a TrueType or OpenType font. The vendored
+// opentype.Face handles metrics, advances and kerning but leaves Glyph and
+// GlyphBounds unimplemented, so this fills them in by rasterizing the glyph
+// outlines itself.
+type outlineFace struct {
+	font.Face
+	f      *sfnt.Font
+	ppem   fixed.Int26_6
+	buf    sfnt.Buffer
+	bounds map[rune]fixed.Rectangle26_6
+}
+
+// newOutlineFace returns f's face at size pixels to the em.
+func newOutlineFace(f *sfnt.Font, size float64) (font.Face, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	return &outlineFace{
+		Face:   face,
+		f:      f,
+		ppem:   fixed.Int26_6(size*64 + 0.5),
+		bounds: map[rune]fixed.Rectangle26_6{},
+	}, nil
+}
+
+// segments loads r's outline. Runes the font lacks get its .notdef glyph,
+// and glyphs that won't load, like colour emoji, come out empty.
+func (f *outlineFace) segments(r rune) []sfnt.Segment {
+	x, _ := f.f.GlyphIndex(&f.buf, r)
+	segments, err := f.f.LoadGlyph(&f.buf, x, f.ppem, nil)
+	if err != nil {
+		return nil
+	}
+	return segments
+}
+
+func (f *outlineFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	advance, _ = f.Face.GlyphAdvance(r)
+	if b, ok := f.bounds[r]; ok {
+		return b, advance, true
+	}
+
+	for i, seg := range f.segments(r) {
+		n := 3
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo, sfnt.SegmentOpLineTo:
+			n = 1
+		case sfnt.SegmentOpQuadTo:
+			n = 2
+		}
+		for j, p := range seg.Args[:n] {
+			if i == 0 && j == 0 {
+				bounds = fixed.Rectangle26_6{Min: p, Max: p}
+				continue
+			}
+			// Union would skip p as an empty rectangle.
+			bounds.Min.X = min(bounds.Min.X, p.X)
+			bounds.Min.Y = min(bounds.Min.Y, p.Y)
+			bounds.Max.X = max(bounds.Max.X, p.X)
+			bounds.Max.Y = max(bounds.Max.Y, p.Y)
+		}
+	}
+	f.bounds[r] = bounds
+	return bounds, advance, true
+}
+
+func (f *outlineFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	bounds, advance, _ := f.GlyphBounds(r)
+	bounds = bounds.Add(dot)
+	dr = image.Rect(bounds.Min.X.Floor(), bounds.Min.Y.Floor(), bounds.Max.X.Ceil(), bounds.Max.Y.Ceil())
+	if dr.Empty() {
+		return dr, image.NewAlpha(dr), dr.Min, advance, true
+	}
+
+	z := vector.NewRasterizer(dr.Dx(), dr.Dy())
+	pt := func(p fixed.Point26_6) (float32, float32) {
+		return float32(p.X+dot.X)/64 - float32(dr.Min.X), float32(p.Y+dot.Y)/64 - float32(dr.Min.Y)
+	}
+	for i, seg := range f.segments(r) {
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			if i > 0 {
+				z.ClosePath()
+			}
+			z.MoveTo(pt(seg.Args[0]))
+		case sfnt.SegmentOpLineTo:
+			z.LineTo(pt(seg.Args[0]))
+		case sfnt.SegmentOpQuadTo:
+			bx, by := pt(seg.Args[0])
+			cx, cy := pt(seg.Args[1])
+			z.QuadTo(bx, by, cx, cy)
+		case sfnt.SegmentOpCubeTo:
+			bx, by := pt(seg.Args[0])
+			cx, cy := pt(seg.Args[1])
+			dx, dy := pt(seg.Args[2])
+			z.CubeTo(bx, by, cx, cy, dx, dy)
+		}
+	}
+	z.ClosePath()
+
+	alpha := image.NewAlpha(image.Rect(0, 0, dr.Dx(), dr.Dy()))
+	z.Draw(alpha, alpha.Bounds(), image.Opaque, image.Point{})
+	return dr, alpha, image.Point{}, advance, true
+}
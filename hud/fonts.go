@@ -0,0 +1,193 @@
+// Package hud draws text for menus and the heads-up display. It builds each
+// glyph atlas once per font and size, loads TrueType and OpenType fonts, and
+// lays out labels with alignment, drop shadows, outlines and anchoring to the
+// edges of the screen.
+package hud
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+// The fonts every Fonts starts with. Basic is the 7x13 bitmap font; the Go
+// fonts are TrueType.
+const (
+	Basic  = "basic"
+	Go     = "go"
+	GoBold = "go-bold"
+)
+
+// basicSize is the pixel size Basic is drawn at unscaled.
+const basicSize = 13
+
+type atlasKey struct {
+	font string
+	size float64
+}
+
+// Fonts holds the loaded fonts and the atlases built from them.
+type Fonts struct {
+	outlines    map[string]*sfnt.Font
+	atlases     map[atlasKey]*text.Atlas
+	runes       []rune
+	substitutes map[string]string
+	// basicMissing is set once runes has some Basic can't draw.
+	basicMissing bool
+}
+
+func NewFonts() *Fonts {
+	f := &Fonts{
+		outlines: map[string]*sfnt.Font{},
+		atlases:  map[atlasKey]*text.Atlas{},
+		runes:    text.ASCII,
+	}
+	// The Go fonts are part of the binary, so parsing them can't fail.
+	if err := f.Parse(Go, goregular.TTF); err != nil {
+		panic(err)
+	}
+	if err := f.Parse(GoBold, gobold.TTF); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Parse adds the TrueType or OpenType font in data under name, replacing any
+// font already called that.
+func (f *Fonts) Parse(name string, data []byte) error {
+	font, err := sfnt.Parse(data)
+	if err != nil {
+		return fmt.Errorf("font %q: %v", name, err)
+	}
+	f.outlines[name] = font
+	for key := range f.atlases {
+		if key.font == name {
+			delete(f.atlases, key)
+		}
+	}
+	return nil
+}
+
+// LoadFS adds every .ttf and .otf file in dir of fsys, each named for its
+// file without the extension. A missing dir adds nothing.
+func (f *Fonts) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		ext := strings.ToLower(path.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".ttf" && ext != ".otf") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := f.Parse(strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Names lists the loaded fonts, Basic included.
+func (f *Fonts) Names() []string {
+	names := []string{Basic}
+	for name := range f.outlines {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// AddRunes makes sure every atlas built from now on covers runes, on top of
+// ASCII. Atlases already built are dropped if they miss any of them.
+func (f *Fonts) AddRunes(runes []rune) {
+	have := map[rune]bool{}
+	for _, r := range f.runes {
+		have[r] = true
+	}
+	added := false
+	for _, r := range runes {
+		if !have[r] {
+			have[r] = true
+			f.runes = append(f.runes, r)
+			added = true
+		}
+	}
+	if added {
+		f.atlases = map[atlasKey]*text.Atlas{}
+	}
+	for _, r := range f.runes {
+		if !basicHas(r) {
+			f.basicMissing = true
+		}
+	}
+}
+
+func basicHas(r rune) bool {
+	for _, rng := range basicfont.Face7x13.Ranges {
+		if rng.Low <= r && r < rng.High {
+			return true
+		}
+	}
+	return false
+}
+
+// Substitute draws text styled in each font of subs in the font it maps to
+// instead, for languages whose glyphs the usual fonts lack. It replaces any
+// earlier substitutes.
+func (f *Fonts) Substitute(subs map[string]string) {
+	f.substitutes = subs
+}
+
+// atlas returns the atlas for name at size, building it the first time, and
+// the scale to draw it at to come out size pixels to the em. Substitutes
+// apply first. Unknown fonts fall back to Basic, and Basic to Go once there
+// are runes it can't draw.
+func (f *Fonts) atlas(name string, size float64) (*text.Atlas, float64) {
+	if sub, ok := f.substitutes[name]; ok {
+		name = sub
+	}
+	if name == Basic && f.basicMissing {
+		name = Go
+	}
+	outline, ok := f.outlines[name]
+	if !ok {
+		// Basic is a bitmap, so there's one atlas for it, scaled to size.
+		name = Basic
+	}
+	key := atlasKey{font: name, size: size}
+	if name == Basic {
+		key.size = basicSize
+	}
+
+	a, ok := f.atlases[key]
+	if !ok {
+		var face font.Face = basicfont.Face7x13
+		if outline != nil {
+			var err error
+			face, err = newOutlineFace(outline, size)
+			if err != nil {
+				face = basicfont.Face7x13
+			}
+		}
+		a = text.NewAtlas(face, f.runes)
+		f.atlases[key] = a
+	}
+	return a, size / key.size
+}
@@ -0,0 +1,206 @@
+package hud
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+)
+
+// Align is how the lines of a label line up with each other.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Anchor is the point of the screen a label is pinned to. The label sits
+// inside the screen from there: a TopRight label hangs down and to the left of
+// the top right corner.
+type Anchor int
+
+const (
+	TopLeft Anchor = iota
+	Top
+	TopRight
+	Left
+	Center
+	Right
+	BottomLeft
+	Bottom
+	BottomRight
+)
+
+// fractions is where along each axis the anchor sits, from 0 at the left or
+// bottom to 1 at the right or top.
+func (a Anchor) fractions() (x, y float64) {
+	x = []float64{0, 0.5, 1}[int(a)%3]
+	y = []float64{1, 0.5, 0}[int(a)/3]
+	return x, y
+}
+
+// Style is how a label's text looks. Size is in pixels to the em. A nil
+// Shadow or Outline leaves it off.
+type Style struct {
+	Font  string
+	Size  float64
+	Color color.Color
+	Align Align
+
+	Shadow       color.Color
+	ShadowOffset pixel.Vec
+
+	Outline      color.Color
+	OutlineWidth float64
+}
+
+// Label is a block of text pinned to an anchor. It only lays its text out
+// again when the text changes, so setting the same text every frame is cheap.
+type Label struct {
+	Style  Style
+	Anchor Anchor
+	// Margin is how far in from the anchor the label sits, or, for the
+	// middle of an edge, how far along it's moved.
+	Margin pixel.Vec
+
+	fonts   *Fonts
+	txt     *text.Text
+	scale   float64
+	content string
+	width   float64
+	lines   int
+}
+
+// Label makes an empty label.
+func (f *Fonts) Label(style Style, anchor Anchor, margin pixel.Vec) *Label {
+	return &Label{Style: style, Anchor: anchor, Margin: margin, fonts: f}
+}
+
+func (l *Label) layout() {
+	atlas, scale := l.fonts.atlas(l.Style.Font, l.Style.Size)
+	if l.txt == nil || l.txt.Atlas() != atlas {
+		l.txt = text.New(pixel.ZV, atlas)
+	}
+	l.scale = scale
+	l.txt.Clear()
+
+	lines := strings.Split(strings.TrimRight(l.content, "\n"), "\n")
+	widths := make([]float64, len(lines))
+	l.width = 0
+	for i, line := range lines {
+		widths[i] = lineWidth(atlas, line)
+		if widths[i] > l.width {
+			l.width = widths[i]
+		}
+	}
+	for i, line := range lines {
+		switch l.Style.Align {
+		case AlignCenter:
+			l.txt.Dot.X += (l.width - widths[i]) / 2
+		case AlignRight:
+			l.txt.Dot.X += l.width - widths[i]
+		}
+		l.txt.WriteString(line)
+		l.txt.WriteByte('\n')
+	}
+	l.lines = len(lines)
+}
+
+// lineWidth is how far line advances the dot, spaces included.
+func lineWidth(atlas *text.Atlas, line string) float64 {
+	width := 0.0
+	prev := rune(-1)
+	for _, r := range line {
+		if !atlas.Contains(r) {
+			r = '�'
+		}
+		if prev >= 0 {
+			width += atlas.Kern(prev, r)
+		}
+		width += atlas.Glyph(r).Advance
+		prev = r
+	}
+	return width
+}
+
+// SetText replaces the label's text.
+func (l *Label) SetText(s string) {
+	atlas, _ := l.fonts.atlas(l.Style.Font, l.Style.Size)
+	if s == l.content && l.txt != nil && l.txt.Atlas() == atlas {
+		return
+	}
+	l.content = s
+	l.layout()
+}
+
+func (l *Label) Printf(format string, args ...interface{}) {
+	l.SetText(fmt.Sprintf(format, args...))
+}
+
+// Size is the label's width and height on screen.
+func (l *Label) Size() pixel.Vec {
+	if l.txt == nil {
+		return pixel.ZV
+	}
+	atlas := l.txt.Atlas()
+	h := atlas.Ascent() + atlas.Descent() + float64(l.lines-1)*atlas.LineHeight()
+	return pixel.V(l.width, h).Scaled(l.scale)
+}
+
+// Rect is where the label lands inside area.
+func (l *Label) Rect(area pixel.Rect) pixel.Rect {
+	return l.Anchor.Place(l.Size(), area, l.Margin)
+}
+
+// Place returns where something of size goes in area at the anchor, margin
+// in from the edges it's anchored to, or along from the middle.
+func (a Anchor) Place(size pixel.Vec, area pixel.Rect, margin pixel.Vec) pixel.Rect {
+	fx, fy := a.fractions()
+	// Margins push in from the edges and along from the middle.
+	mx, my := margin.X*(1-2*fx), margin.Y*(1-2*fy)
+	if fx == 0.5 {
+		mx = margin.X
+	}
+	if fy == 0.5 {
+		my = margin.Y
+	}
+	min := pixel.V(
+		area.Min.X+area.W()*fx+mx-size.X*fx,
+		area.Min.Y+area.H()*fy+my-size.Y*fy,
+	)
+	return pixel.Rect{Min: min, Max: min.Add(size)}
+}
+
+// Draw draws the label inside area: first its shadow, then its outline, then
+// the text itself.
+func (l *Label) Draw(t pixel.Target, area pixel.Rect) {
+	if l.txt == nil || l.content == "" {
+		return
+	}
+	rect := l.Rect(area)
+	// The first line's baseline is an ascent below the top.
+	orig := pixel.V(rect.Min.X, rect.Max.Y-l.txt.Atlas().Ascent()*l.scale)
+	m := pixel.IM.Scaled(pixel.ZV, l.scale).Moved(orig)
+
+	if l.Style.Shadow != nil {
+		l.txt.DrawColorMask(t, m.Moved(l.Style.ShadowOffset), l.Style.Shadow)
+	}
+	if l.Style.Outline != nil && l.Style.OutlineWidth > 0 {
+		w := l.Style.OutlineWidth
+		for _, d := range []pixel.Vec{
+			{X: -w, Y: 0}, {X: w, Y: 0}, {X: 0, Y: -w}, {X: 0, Y: w},
+			{X: -w, Y: -w}, {X: -w, Y: w}, {X: w, Y: -w}, {X: w, Y: w},
+		} {
+			l.txt.DrawColorMask(t, m.Moved(d), l.Style.Outline)
+		}
+	}
+	col := l.Style.Color
+	if col == nil {
+		col = pixel.Alpha(1)
+	}
+	l.txt.DrawColorMask(t, m, col)
+}
@@ -0,0 +1,201 @@
+// Package i18n looks up the game's player-facing text in per-language message
+// catalogs, filling in placeholders and picking plural forms.
+//
+// A catalog is a JSON file named for its language code, such as es.json:
+//
+//	{
+//	  "name": "Español",
+//	  "messages": {
+//	    "hud.score": "Puntos: {score}",
+//	    "gameover.sunk": {"one": "Hundiste {n} barco.", "other": "Hundiste {n} barcos."}
+//	  }
+//	}
+//
+// A message is either a string or an object of plural forms, one of which
+// must be "other". Placeholders are names in braces.
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Args fills a message's placeholders, by name.
+type Args map[string]interface{}
+
+type message struct {
+	text  string
+	forms map[Form]string
+}
+
+// UnmarshalJSON takes either a plain string or an object of plural forms.
+func (m *message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.text); err == nil {
+		return nil
+	}
+	forms := map[Form]string{}
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return fmt.Errorf("want a string or an object of plural forms")
+	}
+	for form := range forms {
+		if !form.valid() {
+			return fmt.Errorf("unknown plural form %q", form)
+		}
+	}
+	if _, ok := forms[Other]; !ok {
+		return fmt.Errorf("plural forms need %q", Other)
+	}
+	m.forms = forms
+	return nil
+}
+
+// Catalog is one language's messages. Keys it lacks come from its fallback,
+// and failing that are shown as the key itself.
+type Catalog struct {
+	// Code is the language code the catalog was loaded as, such as "es".
+	Code string
+	// Name is the language's name in that language, for the settings screen.
+	Name string
+	// Fonts swaps fonts for ones that have this language's glyphs, by name.
+	Fonts map[string]string
+
+	messages map[string]message
+	plural   func(n int) Form
+	fallback *Catalog
+}
+
+type catalogFile struct {
+	Name     string             `json:"name"`
+	Fonts    map[string]string  `json:"fonts"`
+	Messages map[string]message `json:"messages"`
+}
+
+// Parse reads the catalog for the language code from data.
+func Parse(code string, data []byte) (*Catalog, error) {
+	var f catalogFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("catalog %q: %v", code, err)
+	}
+	if f.Name == "" {
+		f.Name = code
+	}
+	return &Catalog{
+		Code:     code,
+		Name:     f.Name,
+		Fonts:    f.Fonts,
+		messages: f.Messages,
+		plural:   pluralRule(code),
+	}, nil
+}
+
+func (c *Catalog) lookup(key string) (message, bool) {
+	for ; c != nil; c = c.fallback {
+		if m, ok := c.messages[key]; ok {
+			return m, true
+		}
+	}
+	return message{}, false
+}
+
+// T returns the message for key with args filled in.
+func (c *Catalog) T(key string, args Args) string {
+	m, ok := c.lookup(key)
+	if !ok {
+		return key
+	}
+	if m.forms != nil {
+		return fill(m.forms[Other], args)
+	}
+	return fill(m.text, args)
+}
+
+// N returns the message for key in the plural form for n, with args filled
+// in and n available as {n}.
+func (c *Catalog) N(key string, n int, args Args) string {
+	m, ok := c.lookup(key)
+	if !ok {
+		return key
+	}
+	all := Args{"n": n}
+	for name, v := range args {
+		all[name] = v
+	}
+	if m.forms == nil {
+		return fill(m.text, all)
+	}
+	text, ok := m.forms[c.plural(n)]
+	if !ok {
+		text = m.forms[Other]
+	}
+	return fill(text, all)
+}
+
+// fill replaces each {name} in text that args has a value for.
+func fill(text string, args Args) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	b := &strings.Builder{}
+	for {
+		open := strings.IndexByte(text, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(text[open:], '}')
+		if end < 0 {
+			break
+		}
+		end += open
+		v, ok := args[text[open+1:end]]
+		if !ok {
+			b.WriteString(text[:end+1])
+		} else {
+			b.WriteString(text[:open])
+			fmt.Fprint(b, v)
+		}
+		text = text[end+1:]
+	}
+	b.WriteString(text)
+	return b.String()
+}
+
+// Keys lists the keys the catalog itself has, leaving out its fallback's.
+func (c *Catalog) Keys() []string {
+	keys := make([]string, 0, len(c.messages))
+	for key := range c.messages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Runes lists every rune the catalog's messages and its fallback's use, so
+// glyph atlases can be built to cover them.
+func (c *Catalog) Runes() []rune {
+	seen := map[rune]bool{}
+	runes := []rune{}
+	add := func(s string) {
+		for _, r := range s {
+			if !seen[r] && r != '\n' {
+				seen[r] = true
+				runes = append(runes, r)
+			}
+		}
+	}
+	for cat := c; cat != nil; cat = cat.fallback {
+		add(cat.Name)
+		for _, m := range cat.messages {
+			add(m.text)
+			for _, text := range m.forms {
+				add(text)
+			}
+		}
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
@@ -0,0 +1,179 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Default is the language every other catalog falls back to. It has to be
+// there.
+const Default = "en"
+
+// Library is every catalog that loaded, by language code.
+type Library struct {
+	catalogs map[string]*Catalog
+}
+
+// LoadFS reads every .json file in dir of fsys as the catalog for the
+// language its name gives, and points each one's fallback at Default.
+func LoadFS(fsys fs.FS, dir string) (*Library, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	l := &Library{catalogs: map[string]*Catalog{}}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		c, err := Parse(strings.TrimSuffix(entry.Name(), ".json"), data)
+		if err != nil {
+			return nil, err
+		}
+		l.catalogs[c.Code] = c
+	}
+	def, ok := l.catalogs[Default]
+	if !ok {
+		return nil, fmt.Errorf("no %s.json catalog in %s", Default, dir)
+	}
+	for _, c := range l.catalogs {
+		if c != def {
+			c.fallback = def
+		}
+	}
+	return l, nil
+}
+
+// Codes lists the languages in the library.
+func (l *Library) Codes() []string {
+	codes := make([]string, 0, len(l.catalogs))
+	for code := range l.catalogs {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Get returns the catalog for code, or nil.
+func (l *Library) Get(code string) *Catalog {
+	return l.catalogs[code]
+}
+
+// Match returns the closest catalog to code: the exact language, then its
+// primary subtag, so "pt_BR" finds pt.json, then Default.
+func (l *Library) Match(code string) *Catalog {
+	code = strings.Replace(code, "_", "-", -1)
+	if c, ok := l.catalogs[code]; ok {
+		return c
+	}
+	if i := strings.IndexByte(code, '-'); i >= 0 {
+		if c, ok := l.catalogs[strings.ToLower(code[:i])]; ok {
+			return c
+		}
+	}
+	if c, ok := l.catalogs[strings.ToLower(code)]; ok {
+		return c
+	}
+	return l.catalogs[Default]
+}
+
+// Missing lists the keys Default has that code's catalog doesn't translate.
+func (l *Library) Missing(code string) []string {
+	c, def := l.catalogs[code], l.catalogs[Default]
+	if c == nil || c == def {
+		return nil
+	}
+	missing := []string{}
+	for _, key := range def.Keys() {
+		if _, ok := c.messages[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// Names lists every catalog's own name for its language, so a language
+// picker can show them all whichever language is active.
+func (l *Library) Names() []string {
+	names := []string{}
+	for _, code := range l.Codes() {
+		names = append(names, l.catalogs[code].Name)
+	}
+	return names
+}
+
+// FromEnv is the language the environment asks for, from LC_ALL,
+// LC_MESSAGES or LANG, such as "pt_BR" for pt_BR.UTF-8. It's empty when they
+// don't say.
+func FromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(name)
+		if i := strings.IndexAny(v, ".@"); i >= 0 {
+			v = v[:i]
+		}
+		if v != "" && v != "C" && v != "POSIX" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Setting is the player's choice of language. An empty Language follows the
+// environment.
+type Setting struct {
+	Language string `json:"language"`
+}
+
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pixelTest", "language.json"), nil
+}
+
+// LoadSetting reads the setting at path. A missing file is the empty
+// setting.
+func LoadSetting(path string) (Setting, error) {
+	var s Setting
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Setting{}, fmt.Errorf("%s: %v", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the setting to path through a temporary file, so a crash
+// can't leave half of it behind.
+func (s Setting) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
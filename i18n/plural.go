@@ -0,0 +1,74 @@
+package i18n
+
+import "strings"
+
+// Form is a plural form, named as in the Unicode CLDR plural rules.
+type Form string
+
+const (
+	Zero  Form = "zero"
+	One   Form = "one"
+	Two   Form = "two"
+	Few   Form = "few"
+	Many  Form = "many"
+	Other Form = "other"
+)
+
+func (f Form) valid() bool {
+	switch f {
+	case Zero, One, Two, Few, Many, Other:
+		return true
+	}
+	return false
+}
+
+// pluralRule picks the plural form for a count in the language code, going
+// by its primary subtag. Languages it doesn't know count like English.
+func pluralRule(code string) func(n int) Form {
+	lang := strings.ToLower(code)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	switch lang {
+	case "fr", "pt":
+		return func(n int) Form {
+			if n == 0 || n == 1 {
+				return One
+			}
+			return Other
+		}
+	case "ru", "uk", "be":
+		return func(n int) Form {
+			switch {
+			case n%10 == 1 && n%100 != 11:
+				return One
+			case slavicFew(n):
+				return Few
+			}
+			return Many
+		}
+	case "pl":
+		return func(n int) Form {
+			switch {
+			case n == 1:
+				return One
+			case slavicFew(n):
+				return Few
+			}
+			return Many
+		}
+	case "ja", "zh", "ko", "vi", "th":
+		return func(n int) Form { return Other }
+	}
+	return func(n int) Form {
+		if n == 1 {
+			return One
+		}
+		return Other
+	}
+}
+
+// slavicFew is 2-4, 22-24, 32-34 and so on, but not 12-14.
+func slavicFew(n int) bool {
+	return n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14)
+}
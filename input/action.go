@@ -0,0 +1,49 @@
+// Package input maps physical keys and mouse buttons onto the abstract actions
+// the game reads, so gameplay never asks about a specific key.
+package input
+
+type Action int
+
+const (
+	MoveLeft Action = iota
+	MoveRight
+	MoveUp
+	MoveDown
+	Fire
+	Pause
+	Confirm
+	Back
+	Quit
+)
+
+// Actions lists every action in the order the controls screen shows them.
+var Actions = []Action{MoveLeft, MoveRight, MoveUp, MoveDown, Fire, Pause, Confirm, Back, Quit}
+
+var actionNames = map[Action]string{
+	MoveLeft:  "move_left",
+	MoveRight: "move_right",
+	MoveUp:    "move_up",
+	MoveDown:  "move_down",
+	Fire:      "fire",
+	Pause:     "pause",
+	Confirm:   "confirm",
+	Back:      "back",
+	Quit:      "quit",
+}
+
+func (a Action) String() string {
+	name, ok := actionNames[a]
+	if !ok {
+		return "invalid"
+	}
+	return name
+}
+
+func parseAction(name string) (Action, bool) {
+	for action, n := range actionNames {
+		if n == name {
+			return action, true
+		}
+	}
+	return 0, false
+}
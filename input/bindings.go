@@ -0,0 +1,114 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// Bindings lists the buttons that trigger each action. Any one of them is
+// enough.
+type Bindings map[Action][]pixelgl.Button
+
+func DefaultBindings() Bindings {
+	return Bindings{
+		MoveLeft:  {pixelgl.KeyLeft, pixelgl.KeyA},
+		MoveRight: {pixelgl.KeyRight, pixelgl.KeyD},
+		MoveUp:    {pixelgl.KeyUp, pixelgl.KeyW},
+		MoveDown:  {pixelgl.KeyDown, pixelgl.KeyS},
+		Fire:      {pixelgl.KeySpace, pixelgl.MouseButtonLeft},
+		Pause:     {pixelgl.KeyP},
+		Confirm:   {pixelgl.KeyEnter},
+		Back:      {pixelgl.KeyEscape, pixelgl.KeyBackspace},
+		Quit:      {pixelgl.KeyEscape},
+	}
+}
+
+// BindingsPath is where the player's bindings are kept between runs.
+func BindingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pixelTest", "bindings.json"), nil
+}
+
+// LoadBindings reads bindings from path. A missing file gives the defaults,
+// and actions missing from the file keep their default buttons.
+func LoadBindings(path string) (Bindings, error) {
+	bindings := DefaultBindings()
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return bindings, nil
+	}
+	if err != nil {
+		return bindings, err
+	}
+
+	var names map[string][]string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return bindings, fmt.Errorf("%s: %v", path, err)
+	}
+
+	for actionName, buttonNames := range names {
+		action, ok := parseAction(actionName)
+		if !ok {
+			return DefaultBindings(), fmt.Errorf("%s: unknown action %q", path, actionName)
+		}
+		buttons := []pixelgl.Button{}
+		for _, buttonName := range buttonNames {
+			button, ok := parseButton(buttonName)
+			if !ok {
+				return DefaultBindings(), fmt.Errorf("%s: %s: unknown button %q", path, actionName, buttonName)
+			}
+			buttons = append(buttons, button)
+		}
+		bindings[action] = buttons
+	}
+	return bindings, nil
+}
+
+func (b Bindings) Save(path string) error {
+	names := map[string][]string{}
+	for action, buttons := range b {
+		buttonNames := []string{}
+		for _, button := range buttons {
+			buttonNames = append(buttonNames, button.String())
+		}
+		names[action.String()] = buttonNames
+	}
+
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// buttons holds every button pixelgl can name, keys and mouse buttons alike.
+var buttons = func() []pixelgl.Button {
+	all := []pixelgl.Button{}
+	for b := pixelgl.Button(0); b <= pixelgl.KeyLast; b++ {
+		if name := b.String(); name != "Invalid" && name != "Unknown" {
+			all = append(all, b)
+		}
+	}
+	return all
+}()
+
+func parseButton(name string) (pixelgl.Button, bool) {
+	for _, b := range buttons {
+		if b.String() == name {
+			return b, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,48 @@
+package input
+
+import "github.com/faiface/pixel/pixelgl"
+
+// Map answers questions about actions by checking every button bound to them
+// on its Source.
+type Map struct {
+	Bindings Bindings
+	src      Source
+}
+
+func New(src Source, bindings Bindings) *Map {
+	return &Map{Bindings: bindings, src: src}
+}
+
+func (m *Map) Pressed(action Action) bool {
+	for _, button := range m.Bindings[action] {
+		if m.src.Pressed(button) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Map) JustPressed(action Action) bool {
+	for _, button := range m.Bindings[action] {
+		if m.src.JustPressed(button) {
+			return true
+		}
+	}
+	return false
+}
+
+// Captured returns a button that was just pressed, if any. The controls screen
+// uses it to learn the button a player wants for an action.
+func (m *Map) Captured() (pixelgl.Button, bool) {
+	for _, button := range buttons {
+		if m.src.JustPressed(button) {
+			return button, true
+		}
+	}
+	return 0, false
+}
+
+// Rebind makes button the only one bound to action.
+func (m *Map) Rebind(action Action, button pixelgl.Button) {
+	m.Bindings[action] = []pixelgl.Button{button}
+}
@@ -0,0 +1,66 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel/pixelgl"
+)
+
+func TestMapChecksEveryBoundButton(t *testing.T) {
+	src := NewFake()
+	m := New(src, DefaultBindings())
+
+	src.Press(pixelgl.KeyA)
+	if !m.Pressed(MoveLeft) || !m.JustPressed(MoveLeft) {
+		t.Error("A, the second button for MoveLeft, didn't trigger it")
+	}
+	if m.Pressed(MoveRight) {
+		t.Error("A triggered MoveRight")
+	}
+
+	src.Update()
+	if !m.Pressed(MoveLeft) || m.JustPressed(MoveLeft) {
+		t.Error("MoveLeft held into the next frame should be pressed, not just pressed")
+	}
+
+	src.Release(pixelgl.KeyA)
+	src.Update()
+	if m.Pressed(MoveLeft) {
+		t.Error("MoveLeft still pressed after A was released")
+	}
+}
+
+func TestMapSharedButton(t *testing.T) {
+	src := NewFake()
+	m := New(src, DefaultBindings())
+	// Escape is bound to both Back and Quit.
+	src.Press(pixelgl.KeyEscape)
+	if !m.JustPressed(Back) || !m.JustPressed(Quit) {
+		t.Error("Escape didn't trigger both Back and Quit")
+	}
+}
+
+func TestCaptureAndRebind(t *testing.T) {
+	src := NewFake()
+	m := New(src, DefaultBindings())
+	if _, ok := m.Captured(); ok {
+		t.Fatal("captured a button with none pressed")
+	}
+
+	src.Press(pixelgl.KeyJ)
+	button, ok := m.Captured()
+	if !ok || button != pixelgl.KeyJ {
+		t.Fatalf("Captured = %v, %v; want J", button, ok)
+	}
+	m.Rebind(Fire, button)
+
+	if !m.JustPressed(Fire) {
+		t.Error("J didn't trigger Fire once rebound")
+	}
+	src.Release(pixelgl.KeyJ)
+	src.Update()
+	src.Press(pixelgl.KeySpace)
+	if m.Pressed(Fire) {
+		t.Error("Space still triggers Fire after the rebind")
+	}
+}
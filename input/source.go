@@ -0,0 +1,48 @@
+package input
+
+import "github.com/faiface/pixel/pixelgl"
+
+// Source reports raw button state. *pixelgl.Window is the real one; Fake
+// stands in for it where there is no window.
+type Source interface {
+	Pressed(button pixelgl.Button) bool
+	JustPressed(button pixelgl.Button) bool
+}
+
+// Fake is a Source driven by hand. Press and Release change what is held, and
+// Update starts a new frame the way Window.Update does, so JustPressed only
+// reports buttons pressed since the last Update.
+type Fake struct {
+	curr map[pixelgl.Button]bool
+	prev map[pixelgl.Button]bool
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		curr: map[pixelgl.Button]bool{},
+		prev: map[pixelgl.Button]bool{},
+	}
+}
+
+func (f *Fake) Press(button pixelgl.Button) {
+	f.curr[button] = true
+}
+
+func (f *Fake) Release(button pixelgl.Button) {
+	delete(f.curr, button)
+}
+
+func (f *Fake) Update() {
+	f.prev = map[pixelgl.Button]bool{}
+	for button := range f.curr {
+		f.prev[button] = true
+	}
+}
+
+func (f *Fake) Pressed(button pixelgl.Button) bool {
+	return f.curr[button]
+}
+
+func (f *Fake) JustPressed(button pixelgl.Button) bool {
+	return f.curr[button] && !f.prev[button]
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/i18n"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/ui"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+// languageScene picks the language, from the system's and every catalog.
+// Choosing one switches to it at once and saves it.
+type languageScene struct {
+	app   *app
+	codes []string
+	list  *ui.List
+	ui    *ui.UI
+}
+
+func newLanguageScene(a *app) *languageScene {
+	// The empty code follows the environment.
+	s := &languageScene{app: a, codes: append([]string{""}, a.languages.Codes()...)}
+	u := a.newUI()
+	s.list = u.List(s.rows(), s.pick)
+	s.list.Selected = indexOf(s.codes, a.language.Language)
+	u.Add(u.VStack(
+		u.StyledLabel(a.tr("language.title"), titleStyle),
+		ui.NewSpace(0, 16),
+		s.list,
+		ui.NewSpace(0, 16),
+		u.Button(a.tr("menu.back"), func() { a.scenes.pop() }),
+		ui.NewSpace(0, 16),
+		u.Label(a.tr("language.hint")),
+	), hud.TopLeft, titleMargin)
+	s.ui = u
+	return s
+}
+
+func (s *languageScene) enter() {}
+
+func (s *languageScene) exit() {}
+
+func (s *languageScene) label(code string) string {
+	if code == "" {
+		system := s.app.languages.Match(i18n.FromEnv())
+		return s.app.catalog.T("language.system", i18n.Args{"name": system.Name})
+	}
+	return s.app.languages.Get(code).Name
+}
+
+func (s *languageScene) rows() []string {
+	rows := make([]string, len(s.codes))
+	for i, code := range s.codes {
+		rows[i] = s.label(code)
+	}
+	return rows
+}
+
+func (s *languageScene) pick(i int) {
+	setting := i18n.Setting{Language: s.codes[i]}
+	s.app.setLanguage(setting)
+	// The system row names the language in the one now active.
+	s.list.Items = s.rows()
+	if s.app.languagePath == "" {
+		return
+	}
+	if err := setting.Save(s.app.languagePath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+func (s *languageScene) update(dt float64) {
+	if s.app.actions.JustPressed(input.Back) {
+		s.app.scenes.pop()
+		return
+	}
+	s.ui.Update(s.app.uiInput(), s.app.world)
+}
+
+func (s *languageScene) draw(win *pixelgl.Window) {
+	win.Clear(colornames.Mediumaquamarine)
+	s.ui.Draw(win, s.app.world)
+}
@@ -1,128 +1,255 @@
 package main
 
 import (
-	_ "image/png"
-	"math/rand"
+	"fmt"
+	"os"
 	"runtime"
 	"time"
 
+	"github.com/TheKaterTot/pixelTest/assets"
+	"github.com/TheKaterTot/pixelTest/config"
+	"github.com/TheKaterTot/pixelTest/display"
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/i18n"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/replay"
+	"github.com/TheKaterTot/pixelTest/scores"
+	"github.com/TheKaterTot/pixelTest/sim"
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/pixelgl"
 )
 
-const padding float64 = 25
+// hudMargin keeps the HUD and the replay controls this far in from the edges
+// of the screen. The game's own padding is Tuning.Padding.
+const hudMargin float64 = 25
 
 var cfg = pixelgl.WindowConfig{
-	Title:  "You Better Work",
-	Bounds: pixel.R(0, 0, 1024, 768),
-	VSync:  true,
+	Title:     "You Better Work",
+	VSync:     true,
+	Resizable: true,
 }
 
-type entity struct {
-	Pos    pixel.Vec
-	Sprite *pixel.Sprite
-	Bounds pixel.Rect
-	Scale  float64
-}
+// loadSprites preloads every asset and picks out the ones the simulation
+// refers to by name, along with the frames and hitboxes it needs for them.
+func loadSprites(m *assets.Manager) (map[string]*pixel.Sprite, map[string]sim.SpriteDef, error) {
+	if err := m.Preload(); err != nil {
+		return nil, nil, err
+	}
 
-func getInitialPos(sprite *pixel.Sprite, scale float64) pixel.Vec {
-	x, y := sprite.Frame().Size().XY()
-	x = x * scale
-	y = y * scale
-	return pixel.V(x/2+padding, y/2+padding)
+	sprites := map[string]*pixel.Sprite{}
+	defs := map[string]sim.SpriteDef{}
+	for _, name := range []string{sim.PlayerSprite, sim.EnemySprite, sim.MissileSprite} {
+		sprite, err := m.Sprite(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		hitbox, err := m.Hitbox(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		sprites[name] = sprite
+		defs[name] = sim.SpriteDef{Frame: sprite.Frame(), Hitbox: hitbox}
+	}
+	return sprites, defs, nil
 }
 
-func getBounds(sprite *entity) pixel.Rect {
-	width := sprite.Sprite.Frame().W() * sprite.Scale
-	height := sprite.Sprite.Frame().H() * sprite.Scale
-	x := sprite.Pos.X - (width / 2.0)
-	y := sprite.Pos.Y - (height / 2.0)
-	return pixel.R(x, y, width+x, height+y)
+func init() {
+	runtime.LockOSThread()
 }
 
-func newEntityFromSprite(imgPath string) (*entity, error) {
-	scale := 0.065
-	pic, err := loadPicture(imgPath)
-	if err != nil {
-		return nil, err
-	}
-
-	sprite := pixel.NewSprite(pic, pic.Bounds())
-	pos := getInitialPos(sprite, scale)
-	return &entity{Pos: pos, Sprite: sprite, Scale: scale}, nil
+func main() {
+	os.Exit(dispatch(os.Args[1:]))
 }
 
-func placenewSprite() (*entity, error) {
-	return newEntityFromSprite("./images/player.png")
+// session is everything a command settles on before the window opens.
+type session struct {
+	config config.Config
+	// fullscreen overrides the saved display mode for this run.
+	fullscreen bool
+	// seed, when not zero, seeds every game in place of the clock.
+	seed int64
+	// language, when set, overrides the saved language for this run.
+	language string
+	// difficulty names the game mode, for the high-score table.
+	difficulty string
+	languages  *i18n.Library
+	assets     *assets.Manager
+	sprites    map[string]*pixel.Sprite
+	spriteDefs map[string]sim.SpriteDef
+	replay     *replay.Replay
+	exit       int
 }
 
-func newMissile(pos pixel.Vec) (*entity, error) {
-	imgPath := "./images/missile.png"
-	scale := 0.035
+// current is the session run plays. pixelgl.Run takes no arguments, so it's
+// handed over here.
+var current *session
 
-	pic, err := loadPicture(imgPath)
-	if err != nil {
-		return nil, err
-	}
-
-	sprite := pixel.NewSprite(pic, pic.Bounds())
-	return &entity{Pos: pos, Sprite: sprite, Scale: scale}, nil
+// start opens the window on s and plays it until the window closes, returning
+// the exit code.
+func start(s *session) int {
+	current = s
+	pixelgl.Run(run)
+	return s.exit
 }
 
-func newPlayerMissle(player *entity) (*entity, error) {
-	return newMissile(player.Pos)
+// app is what every scene shares: the window, the player's controls, the
+// loaded sprites and the scene stack itself. Scenes draw in world
+// coordinates, inside world; the camera fits that to the window.
+type app struct {
+	win          *pixelgl.Window
+	world        pixel.Rect
+	camera       *camera
+	config       config.Config
+	display      display.Settings
+	displayPath  string
+	seed         int64
+	mode         string
+	actions      *input.Map
+	bindingsPath string
+	fonts        *hud.Fonts
+	languages    *i18n.Library
+	language     i18n.Setting
+	languagePath string
+	catalog      *i18n.Catalog
+	scores       *scores.Table
+	scoresPath   string
+	assets       *assets.Manager
+	sprites      map[string]*pixel.Sprite
+	spriteDefs   map[string]sim.SpriteDef
+	scenes       *scenes
 }
 
-func playerFire(player *entity) (*entity, error) {
-	return newPlayerMissle(player)
-}
+func run() {
+	s := current
+	conf := s.config
+	displayPath, err := display.Path()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	disp, err := display.Load(displayPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if s.fullscreen {
+		disp.Mode = display.Fullscreen
+	}
 
-func isMissileOffWorld(x float64) bool {
-	if x > 1024 {
-		return true
+	cfg.Bounds = pixel.R(0, 0, float64(conf.Window.Width), float64(conf.Window.Height))
+	cfg.VSync = disp.VSync
+	win, err := pixelgl.NewWindow(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		s.exit = exitFailure
+		return
+	}
+	if disp.Mode != display.Windowed {
+		disp.Apply(win)
 	}
-	return false
-}
 
-func init() {
-	runtime.LockOSThread()
-	rand.Seed(time.Now().Unix())
-}
+	bindingsPath, err := input.BindingsPath()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	bindings, err := input.LoadBindings(bindingsPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
 
-func main() {
-	pixelgl.Run(run)
-}
+	fonts := hud.NewFonts()
+	if err := fonts.LoadFS(s.assets.FS(), fontsDir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
 
-func run() {
-	g := newGame()
+	languagePath, err := i18n.Path()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	language, err := i18n.LoadSetting(languagePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if s.language != "" {
+		language.Language = s.language
+	}
 
-	win, err := pixelgl.NewWindow(cfg)
+	scoresPath, err := scores.Path()
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+	}
+	table, err := scores.Load(scoresPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	for _, e := range table.Entries {
+		fonts.AddRunes([]rune(e.Initials))
 	}
 
-	g.running = false
-	for !win.Closed() && !g.running {
-		g.gameStart(win)
-		if win.JustPressed(pixelgl.KeyEnter) {
-			g.running = true
-			break
-		}
+	a := &app{
+		win:          win,
+		world:        conf.World.Bounds(),
+		camera:       newCamera(conf.World.Bounds(), conf.Window.IntegerScale),
+		config:       conf,
+		display:      disp,
+		displayPath:  displayPath,
+		seed:         s.seed,
+		mode:         s.difficulty,
+		actions:      input.New(win, bindings),
+		bindingsPath: bindingsPath,
+		fonts:        fonts,
+		languages:    s.languages,
+		languagePath: languagePath,
+		scores:       table,
+		scoresPath:   scoresPath,
+		assets:       s.assets,
+		sprites:      s.sprites,
+		spriteDefs:   s.spriteDefs,
+		scenes:       &scenes{},
 	}
+	a.setLanguage(language)
+	if s.replay != nil {
+		a.scenes.push(newReplayScene(a, s.replay))
+	} else {
+		a.scenes.push(newTitleScene(a))
+	}
+	a.scenes.apply()
 
-	for !win.Closed() {
-		for g.running && !win.Closed() {
-			if !g.running {
-				break
-			}
-			g.input(win)
-			g.draw(win)
-			g.update(win)
-		}
-		g.gameOver(win)
-		if win.JustPressed(pixelgl.KeyEnter) {
-			g.running = true
-			g = newGame()
+	last := time.Now()
+	for !win.Closed() && !a.scenes.empty() {
+		dt := time.Since(last).Seconds()
+		last = time.Now()
+
+		a.scenes.update(dt)
+		a.camera.begin(win)
+		a.scenes.draw(win)
+		a.camera.end(win)
+		win.Update()
+
+		if frame := a.display.FrameTime(); frame > 0 {
+			time.Sleep(frame - time.Since(last))
 		}
 	}
+	a.scenes.close()
+}
+
+// setLanguage switches to the catalog the setting picks and makes sure the
+// fonts can draw it. Every language's name is drawn on the language screen,
+// so those runes are added whichever is picked.
+func (a *app) setLanguage(s i18n.Setting) {
+	a.language = s
+	code := s.Language
+	if code == "" {
+		code = i18n.FromEnv()
+	}
+	a.catalog = a.languages.Match(code)
+	a.fonts.AddRunes(a.catalog.Runes())
+	for _, name := range a.languages.Names() {
+		a.fonts.AddRunes([]rune(name))
+	}
+	a.fonts.Substitute(a.catalog.Fonts)
+}
+
+// t is the active catalog's message for key, for messages without
+// placeholders.
+func (a *app) t(key string) string {
+	return a.catalog.T(key, nil)
 }
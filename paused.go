@@ -0,0 +1,63 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/ui"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// dimColor is laid over the frozen game while it's paused.
+var dimColor = color.RGBA{A: 160}
+
+// pausedScene sits over a game that's stopped. The game underneath isn't
+// updated, so it goes on drawing the frame it stopped on, dimmed.
+type pausedScene struct {
+	app  *app
+	game *game
+	dim  *imdraw.IMDraw
+	ui   *ui.UI
+}
+
+func newPausedScene(a *app, g *game) *pausedScene {
+	dim := imdraw.New(nil)
+	dim.Color = dimColor
+	dim.Push(a.world.Min, a.world.Max)
+	dim.Rectangle(0)
+
+	u := a.newUI()
+	menu := u.VStack(
+		u.Button(a.tr("menu.resume"), func() { a.scenes.pop() }),
+		u.Button(a.tr("menu.restart"), func() { a.scenes.replace(newPlayingScene(a)) }),
+		u.Button(a.tr("menu.save"), func() { a.scenes.push(newSlotsScene(a, g)) }),
+		u.Button(a.tr("menu.settings"), func() { a.scenes.push(newSettingsScene(a)) }),
+		u.Button(a.tr("menu.quit_to_title"), func() { a.scenes.replace(newTitleScene(a)) }),
+		u.Button(a.tr("menu.quit"), func() { a.win.SetClosed(true) }),
+	)
+	menu.Stretch = true
+	content := u.VStack(u.StyledLabel(a.tr("paused.title"), titleStyle), menu)
+	content.Align = ui.Middle
+	u.Add(u.Panel(content), hud.Center, pixel.ZV)
+	return &pausedScene{app: a, game: g, dim: dim, ui: u}
+}
+
+func (s *pausedScene) enter() {}
+
+func (s *pausedScene) exit() {}
+
+func (s *pausedScene) update(dt float64) {
+	if s.app.actions.JustPressed(input.Pause) || s.app.actions.JustPressed(input.Back) {
+		s.app.scenes.pop()
+		return
+	}
+	s.ui.Update(s.app.uiInput(), s.app.world)
+}
+
+func (s *pausedScene) draw(win *pixelgl.Window) {
+	s.dim.Draw(win)
+	s.ui.Draw(win, s.app.world)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/saves"
+	"github.com/TheKaterTot/pixelTest/sim"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// maxFrame caps how much simulated time one frame may catch up on, so a stall
+// (dragging the window, a debugger) doesn't turn into a burst of ticks.
+const maxFrame = 0.25
+
+type playingScene struct {
+	app  *app
+	game *game
+	acc  float64
+}
+
+func newPlayingScene(a *app) *playingScene {
+	seed := a.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &playingScene{app: a, game: newGame(a.world, a.fonts, a.sprites, a.spriteDefs, a.config.Game, seed)}
+}
+
+// resumePlayingScene carries on a game loaded from a save.
+func resumePlayingScene(a *app, world *sim.Game) *playingScene {
+	return &playingScene{app: a, game: newGameView(world, a.fonts, a.sprites)}
+}
+
+func (s *playingScene) enter() {}
+
+// exit records the game, whether it ended or the window was closed on it. A
+// game cut short by closing the window is also autosaved.
+func (s *playingScene) exit() {
+	if err := s.game.saveReplay(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if s.game.world.Running && s.app.win.Closed() {
+		if err := saves.Write(saves.Autosave, s.game.world); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+func (s *playingScene) update(dt float64) {
+	// Quit asks first, through the pause menu. Losing focus pauses too, so
+	// the game doesn't go on without the player.
+	actions := s.app.actions
+	if actions.JustPressed(input.Pause) || actions.JustPressed(input.Quit) || !s.app.win.Focused() {
+		s.app.scenes.push(newPausedScene(s.app, s.game))
+		return
+	}
+
+	s.acc += math.Min(dt, maxFrame)
+	s.acc = s.game.advance(s.app.actions, s.acc)
+
+	if !s.game.world.Running {
+		s.app.scenes.replace(newGameOverScene(s.app, s.game))
+	}
+}
+
+func (s *playingScene) draw(win *pixelgl.Window) {
+	s.game.draw(win, s.acc/sim.Tick)
+	s.game.displayHUD(win, s.app.world, s.app.catalog)
+}
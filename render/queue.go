@@ -0,0 +1,50 @@
+// Package render collects sprite draws over a frame and submits them grouped
+// by picture, one pixel.Batch per picture, instead of one draw call per sprite.
+package render
+
+import (
+	"image/color"
+
+	"github.com/faiface/pixel"
+)
+
+type Queue struct {
+	batches map[pixel.Picture]*pixel.Batch
+	// order holds pictures in the order they were first drawn, so the layering
+	// between pictures stays stable from frame to frame.
+	order []pixel.Picture
+}
+
+func NewQueue() *Queue {
+	return &Queue{batches: map[pixel.Picture]*pixel.Batch{}}
+}
+
+func (q *Queue) batch(pic pixel.Picture) *pixel.Batch {
+	b, ok := q.batches[pic]
+	if !ok {
+		b = pixel.NewBatch(&pixel.TrianglesData{}, pic)
+		q.batches[pic] = b
+		q.order = append(q.order, pic)
+	}
+	return b
+}
+
+// Draw queues sprite to be drawn with matrix m at the next Flush.
+func (q *Queue) Draw(sprite *pixel.Sprite, m pixel.Matrix) {
+	sprite.Draw(q.batch(sprite.Picture()), m)
+}
+
+// DrawColorMask is Draw with sprite's colors multiplied by mask.
+func (q *Queue) DrawColorMask(sprite *pixel.Sprite, m pixel.Matrix, mask color.Color) {
+	sprite.DrawColorMask(q.batch(sprite.Picture()), m, mask)
+}
+
+// Flush draws everything queued since the last Flush onto t, one batch per
+// picture, and empties the queue.
+func (q *Queue) Flush(t pixel.Target) {
+	for _, pic := range q.order {
+		b := q.batches[pic]
+		b.Draw(t)
+		b.Clear()
+	}
+}
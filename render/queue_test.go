@@ -0,0 +1,94 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+// countingTarget stands in for a window. Like a GL target it copies vertex
+// data when it's handed triangles, and it counts draw calls.
+type countingTarget struct {
+	draws int
+}
+
+type countingTriangles struct {
+	*pixel.TrianglesData
+	dst *countingTarget
+}
+
+func (t *countingTriangles) Draw() {
+	t.dst.draws++
+}
+
+type countingPicture struct {
+	pixel.Picture
+	dst *countingTarget
+}
+
+func (p *countingPicture) Draw(t pixel.TargetTriangles) {
+	t.Draw()
+}
+
+func (t *countingTarget) MakeTriangles(tri pixel.Triangles) pixel.TargetTriangles {
+	return &countingTriangles{TrianglesData: pixel.MakeTrianglesData(tri.Len()), dst: t}
+}
+
+func (t *countingTarget) MakePicture(pic pixel.Picture) pixel.TargetPicture {
+	return &countingPicture{Picture: pic, dst: t}
+}
+
+func sprites() []*pixel.Sprite {
+	sprites := []*pixel.Sprite{}
+	for _, size := range []int{64, 32, 16} {
+		pic := pixel.PictureDataFromImage(image.NewRGBA(image.Rect(0, 0, size, size)))
+		sprites = append(sprites, pixel.NewSprite(pic, pic.Bounds()))
+	}
+	return sprites
+}
+
+func matrices(n int) []pixel.Matrix {
+	ms := make([]pixel.Matrix, n)
+	for i := range ms {
+		ms[i] = pixel.IM.Scaled(pixel.ZV, 0.065).Moved(pixel.V(float64(i%1024), float64(i%768)))
+	}
+	return ms
+}
+
+var entityCounts = []int{10, 100, 1000, 5000}
+
+func BenchmarkDirect(b *testing.B) {
+	for _, n := range entityCounts {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			sprites, ms := sprites(), matrices(n)
+			t := &countingTarget{}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j, m := range ms {
+					sprites[j%len(sprites)].Draw(t, m)
+				}
+			}
+			b.ReportMetric(float64(t.draws)/float64(b.N), "draws/op")
+		})
+	}
+}
+
+func BenchmarkQueue(b *testing.B) {
+	for _, n := range entityCounts {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			sprites, ms := sprites(), matrices(n)
+			t := &countingTarget{}
+			q := NewQueue()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j, m := range ms {
+					q.Draw(sprites[j%len(sprites)], m)
+				}
+				q.Flush(t)
+			}
+			b.ReportMetric(float64(t.draws)/float64(b.N), "draws/op")
+		})
+	}
+}
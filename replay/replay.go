@@ -0,0 +1,240 @@
+// Package replay records a game as its seed and the input of every tick, and
+// plays such recordings back. Since the simulation is deterministic, that's
+// all it takes to reproduce a run exactly.
+package replay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/TheKaterTot/pixelTest/sim"
+	"github.com/faiface/pixel"
+)
+
+// Version 2 added the tuning. Version 1 recordings were all made with the
+// default tuning, and still play back with it. Version 3 added fire being
+// held, for rapid fire.
+const (
+	magic   = "PXRP"
+	version = 3
+)
+
+var ErrDiverged = errors.New("replay: playback diverged from the recording")
+
+// Replay is a recorded game. Score is what the game ended on, so playback
+// can check it got the same result.
+type Replay struct {
+	Seed   int64
+	Bounds pixel.Rect
+	Tuning sim.Tuning
+	Inputs []sim.Input
+	Score  int64
+}
+
+// Dir is where finished games are recorded.
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pixelTest", "replays"), nil
+}
+
+// Record appends one tick's input.
+func (r *Replay) Record(in sim.Input) {
+	r.Inputs = append(r.Inputs, in)
+}
+
+func inputBits(in sim.Input) byte {
+	var b byte
+	for i, pressed := range []bool{in.Left, in.Right, in.Up, in.Down, in.Fire, in.Firing} {
+		if pressed {
+			b |= 1 << uint(i)
+		}
+	}
+	return b
+}
+
+func bitsInput(b byte) sim.Input {
+	return sim.Input{
+		Left:   b&(1<<0) != 0,
+		Right:  b&(1<<1) != 0,
+		Up:     b&(1<<2) != 0,
+		Down:   b&(1<<3) != 0,
+		Fire:   b&(1<<4) != 0,
+		Firing: b&(1<<5) != 0,
+	}
+}
+
+// Write saves r. The tuning is stored as length-prefixed JSON, so adding a
+// field to it doesn't need a new version. After the header, inputs are
+// stored as runs of identical ticks, one varint count and one bitmask byte
+// per run, which keeps long stretches of holding a direction down to a few
+// bytes.
+func (r *Replay) Write(w io.Writer) error {
+	tuning, err := json.Marshal(r.Tuning)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, binary.MaxVarintLen64)
+
+	bw.WriteString(magic)
+	bw.WriteByte(version)
+	bw.Write(buf[:binary.PutVarint(buf, r.Seed)])
+	for _, f := range []float64{r.Bounds.Min.X, r.Bounds.Min.Y, r.Bounds.Max.X, r.Bounds.Max.Y} {
+		binary.Write(bw, binary.LittleEndian, math.Float64bits(f))
+	}
+	bw.Write(buf[:binary.PutUvarint(buf, uint64(len(tuning)))])
+	bw.Write(tuning)
+	bw.Write(buf[:binary.PutVarint(buf, r.Score)])
+	bw.Write(buf[:binary.PutUvarint(buf, uint64(len(r.Inputs)))])
+
+	for i := 0; i < len(r.Inputs); {
+		bits := inputBits(r.Inputs[i])
+		run := 1
+		for i+run < len(r.Inputs) && inputBits(r.Inputs[i+run]) == bits {
+			run++
+		}
+		bw.Write(buf[:binary.PutUvarint(buf, uint64(run))])
+		bw.WriteByte(bits)
+		i += run
+	}
+	return bw.Flush()
+}
+
+// legacyTuning is what a recording plays with where its tuning doesn't say:
+// the defaults, less the lives, health, enemy classes and loot that came after
+// the first recordings.
+func legacyTuning() sim.Tuning {
+	t := sim.DefaultTuning()
+	t.Lives = 0
+	t.ExtraLives = nil
+	t.ExtraLifeEvery = 0
+	t.Invulnerable = 0
+	t.PlayerHealth = 0
+	t.PlayerDamage = 0
+	t.MissileDamage = 0
+	t.EnemyMissileDamage = 0
+	t.HitFlash = 0
+	t.Enemies = nil
+	t.Loot = sim.Loot{}
+	return t
+}
+
+func Read(r io.Reader) (*Replay, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, err
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, fmt.Errorf("replay: not a replay file")
+	}
+	v := header[len(magic)]
+	if v < 1 || v > version {
+		return nil, fmt.Errorf("replay: unsupported version %d", v)
+	}
+
+	rep := &Replay{Tuning: legacyTuning()}
+	var err error
+	if rep.Seed, err = binary.ReadVarint(br); err != nil {
+		return nil, err
+	}
+	bounds := make([]float64, 4)
+	for i := range bounds {
+		var bits uint64
+		if err := binary.Read(br, binary.LittleEndian, &bits); err != nil {
+			return nil, err
+		}
+		bounds[i] = math.Float64frombits(bits)
+	}
+	rep.Bounds = pixel.R(bounds[0], bounds[1], bounds[2], bounds[3])
+	if v >= 2 {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		if n > 1<<16 {
+			return nil, fmt.Errorf("replay: corrupt tuning")
+		}
+		tuning := make([]byte, n)
+		if _, err := io.ReadFull(br, tuning); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(tuning, &rep.Tuning); err != nil {
+			return nil, fmt.Errorf("replay: corrupt tuning: %v", err)
+		}
+	}
+	if rep.Score, err = binary.ReadVarint(br); err != nil {
+		return nil, err
+	}
+	ticks, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+
+	for uint64(len(rep.Inputs)) < ticks {
+		run, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		bits, err := br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if run == 0 || uint64(len(rep.Inputs))+run > ticks {
+			return nil, fmt.Errorf("replay: corrupt input stream")
+		}
+		in := bitsInput(bits)
+		for i := uint64(0); i < run; i++ {
+			rep.Inputs = append(rep.Inputs, in)
+		}
+	}
+	return rep, nil
+}
+
+func Load(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+func (r *Replay) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Play runs the whole recording through a fresh game and returns it. It
+// reports ErrDiverged if the game doesn't end on the recorded score.
+func (r *Replay) Play(defs map[string]sim.SpriteDef) (*sim.Game, error) {
+	g := sim.New(r.Bounds, defs, r.Tuning, r.Seed)
+	for _, in := range r.Inputs {
+		g.Step(in)
+	}
+	if g.Score != r.Score {
+		return g, ErrDiverged
+	}
+	return g, nil
+}
@@ -0,0 +1,151 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/TheKaterTot/pixelTest/sim"
+	"github.com/faiface/pixel"
+)
+
+var (
+	testBounds = pixel.R(0, 0, 1024, 768)
+	testDefs   = map[string]sim.SpriteDef{
+		sim.PlayerSprite:  {Frame: pixel.R(0, 0, 800, 600)},
+		sim.EnemySprite:   {Frame: pixel.R(0, 0, 800, 600)},
+		sim.MissileSprite: {Frame: pixel.R(0, 0, 600, 300)},
+	}
+)
+
+// record plays ticks of scripted input and returns the recording.
+func record(t sim.Tuning, seed int64, ticks int) *Replay {
+	r := &Replay{Seed: seed, Bounds: testBounds, Tuning: t}
+	g := sim.New(r.Bounds, testDefs, t, seed)
+	rng := sim.NewRNG(seed + 1)
+	for i := 0; i < ticks && g.Running; i++ {
+		fire := rng.Float64() < 0.1
+		in := sim.Input{Up: rng.Float64() < 0.3, Down: rng.Float64() < 0.3, Fire: fire, Firing: fire || rng.Float64() < 0.5}
+		r.Record(in)
+		g.Step(in)
+	}
+	r.Score = g.Score
+	return r
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := record(sim.DefaultTuning(), 3, 60*60)
+	if r.Score == 0 {
+		t.Fatal("the recorded game scored nothing, so it can't tell a good playback from a bad one")
+	}
+	buf := &bytes.Buffer{}
+	if err := r.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Errorf("read back %+v, want %+v", got, r)
+	}
+	if _, err := got.Play(testDefs); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPlayDetectsDivergence(t *testing.T) {
+	r := record(sim.DefaultTuning(), 3, 60*60)
+	r.Score++
+	if _, err := r.Play(testDefs); err != ErrDiverged {
+		t.Errorf("got %v, want ErrDiverged", err)
+	}
+}
+
+// writeLegacy writes r the way versions before the current one did: version
+// 1 without the tuning, version 2 with it but without fire being held.
+func writeLegacy(r *Replay, version byte, tuning string) []byte {
+	buf := &bytes.Buffer{}
+	bw := bufio.NewWriter(buf)
+	varint := make([]byte, binary.MaxVarintLen64)
+	bw.WriteString(magic)
+	bw.WriteByte(version)
+	bw.Write(varint[:binary.PutVarint(varint, r.Seed)])
+	for _, f := range []float64{r.Bounds.Min.X, r.Bounds.Min.Y, r.Bounds.Max.X, r.Bounds.Max.Y} {
+		binary.Write(bw, binary.LittleEndian, math.Float64bits(f))
+	}
+	if version >= 2 {
+		bw.Write(varint[:binary.PutUvarint(varint, uint64(len(tuning)))])
+		bw.WriteString(tuning)
+	}
+	bw.Write(varint[:binary.PutVarint(varint, r.Score)])
+	bw.Write(varint[:binary.PutUvarint(varint, uint64(len(r.Inputs)))])
+	for _, in := range r.Inputs {
+		bw.Write(varint[:binary.PutUvarint(varint, 1)])
+		bw.WriteByte(inputBits(in) &^ (1 << 5))
+	}
+	bw.Flush()
+	return buf.Bytes()
+}
+
+func TestReadLegacy(t *testing.T) {
+	// The tuning every version 1 recording was made with, and how a version
+	// 2 one from then stored it.
+	legacy := legacyTuning()
+	v2 := `{"padding":25,"player_speed":180,"enemy_speed":90,"missile_speed":210,` +
+		`"enemy_fire_rate":0.156,"enemy_missile_mul":1.5,"enemy_missile_lifetime":10,` +
+		`"max_enemies":6,"player_scale":0.065,"enemy_scale":0.065,"missile_scale":0.035}`
+	v2Tuning := legacy
+	v2Tuning.MaxEnemies = 6
+
+	for _, c := range []struct {
+		version byte
+		tuning  sim.Tuning
+	}{
+		{1, legacy},
+		{2, v2Tuning},
+	} {
+		r := record(c.tuning, 5, 60*60)
+		for i := range r.Inputs {
+			r.Inputs[i].Firing = false
+		}
+		r.Score = 0
+		g, _ := r.Play(testDefs)
+		r.Score = g.Score
+
+		got, err := Read(bytes.NewReader(writeLegacy(r, c.version, v2)))
+		if err != nil {
+			t.Fatalf("version %d: %v", c.version, err)
+		}
+		if !reflect.DeepEqual(got.Tuning, c.tuning) {
+			t.Errorf("version %d: got tuning %+v, want %+v", c.version, got.Tuning, c.tuning)
+		}
+		if g, err := got.Play(testDefs); err != nil || g.Lives > 1 {
+			t.Errorf("version %d: played back with %v, %d lives; want the recorded score on one life", c.version, err, g.Lives)
+		}
+	}
+}
+
+// TestPlayOldRecording plays back a version 2 file written by the game from
+// before lives and health: seed 199, recorded with the same scripted input as
+// record but firing half the time, until the game ended.
+func TestPlayOldRecording(t *testing.T) {
+	r, err := Load(filepath.Join("testdata", "v2_seed199.pxr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Seed != 199 || len(r.Inputs) != 501 || r.Score != 3 {
+		t.Fatalf("got seed %d, %d ticks, score %d; want 199, 501 and 3", r.Seed, len(r.Inputs), r.Score)
+	}
+	g, err := r.Play(testDefs)
+	if err != nil {
+		t.Fatalf("played back to score %d: %v", g.Score, err)
+	}
+	if g.Running {
+		t.Error("the game was still running at the end of the recording")
+	}
+}
@@ -0,0 +1,73 @@
+package replay
+
+import "github.com/TheKaterTot/pixelTest/sim"
+
+// SnapshotEvery is how many ticks apart a Timeline keeps snapshots. Seeking
+// never has to re-simulate more than this many ticks.
+const SnapshotEvery = 300
+
+// Timeline plays a Replay and can jump to any tick in it. It runs the whole
+// recording once up front, cloning the game every SnapshotEvery ticks, and
+// seeks by restoring the nearest earlier snapshot and stepping forward.
+type Timeline struct {
+	Game *sim.Game
+	Tick int
+	// Diverged is set when the recording didn't end on its recorded score,
+	// usually because the sprites or hitboxes changed since it was made.
+	Diverged bool
+
+	replay    *Replay
+	snapshots []*sim.Game
+}
+
+func NewTimeline(r *Replay, defs map[string]sim.SpriteDef) *Timeline {
+	t := &Timeline{replay: r}
+
+	g := sim.New(r.Bounds, defs, r.Tuning, r.Seed)
+	for tick, in := range r.Inputs {
+		if tick%SnapshotEvery == 0 {
+			t.snapshots = append(t.snapshots, g.Clone())
+		}
+		g.Step(in)
+	}
+	if len(r.Inputs)%SnapshotEvery == 0 {
+		t.snapshots = append(t.snapshots, g.Clone())
+	}
+	t.Diverged = g.Score != r.Score
+
+	t.Seek(0)
+	return t
+}
+
+// Len is the number of ticks in the recording.
+func (t *Timeline) Len() int {
+	return len(t.replay.Inputs)
+}
+
+// Step plays one tick, and reports false once there are none left.
+func (t *Timeline) Step() bool {
+	if t.Tick >= t.Len() {
+		return false
+	}
+	t.Game.Step(t.replay.Inputs[t.Tick])
+	t.Tick++
+	return true
+}
+
+// Seek moves to tick, clamped to the recording. It always gives Game a fresh
+// value, never one shared with a snapshot.
+func (t *Timeline) Seek(tick int) {
+	if tick < 0 {
+		tick = 0
+	}
+	if tick > t.Len() {
+		tick = t.Len()
+	}
+
+	i := tick / SnapshotEvery
+	t.Game = t.snapshots[i].Clone()
+	t.Tick = i * SnapshotEvery
+	for t.Tick < tick {
+		t.Step()
+	}
+}
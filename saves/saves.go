@@ -0,0 +1,102 @@
+// Package saves keeps games in named slots on disk so a run can be picked up
+// again later.
+package saves
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TheKaterTot/pixelTest/sim"
+)
+
+// Autosave is the slot the game saves to by itself when the window is closed
+// mid-game.
+const Autosave = "autosave"
+
+// Slots lists every slot, the autosave first.
+var Slots = []string{Autosave, "slot1", "slot2", "slot3"}
+
+// File is what a slot holds on disk.
+type File struct {
+	SavedAt time.Time
+	State   *sim.SaveState
+}
+
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pixelTest", "saves"), nil
+}
+
+func path(slot string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, slot+".json"), nil
+}
+
+// Write saves g into slot. It writes a temporary file and renames it over the
+// old one, so a crash mid-save never leaves a slot half written.
+func Write(slot string, g *sim.Game) error {
+	p, err := path(slot)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(File{SavedAt: time.Now(), State: g.State()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+// Read loads slot. An empty slot gives a nil File and no error.
+func Read(slot string) (*File, error) {
+	p, err := path(slot)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, err
+	}
+	if f.State == nil {
+		return nil, errors.New("save has no game in it")
+	}
+	return f, nil
+}
+
+// Load reads slot and restores the game in it.
+func Load(slot string, defs map[string]sim.SpriteDef) (*sim.Game, error) {
+	f, err := Read(slot)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, errors.New("slot " + slot + " is empty")
+	}
+	return sim.Restore(f.State, defs)
+}
@@ -0,0 +1,103 @@
+package main
+
+import "github.com/faiface/pixel/pixelgl"
+
+// scene is one screen of the game. Only the scene on top of the stack is
+// updated, but every scene in the stack is drawn, bottom first, so overlays
+// like the pause screen sit over the frame beneath them.
+type scene interface {
+	enter()
+	update(dt float64)
+	draw(win *pixelgl.Window)
+	exit()
+}
+
+type transitionKind int
+
+const (
+	pushScene transitionKind = iota
+	popScene
+	replaceScene
+)
+
+type transition struct {
+	kind  transitionKind
+	scene scene
+}
+
+// scenes is the scene stack. Scenes ask for transitions while they update and
+// the stack applies them afterwards, so a scene never exits halfway through
+// its own update.
+type scenes struct {
+	stack   []scene
+	pending []transition
+}
+
+func (s *scenes) push(sc scene) {
+	s.pending = append(s.pending, transition{kind: pushScene, scene: sc})
+}
+
+func (s *scenes) pop() {
+	s.pending = append(s.pending, transition{kind: popScene})
+}
+
+// replace exits every scene in the stack and starts over with sc.
+func (s *scenes) replace(sc scene) {
+	s.pending = append(s.pending, transition{kind: replaceScene, scene: sc})
+}
+
+// close exits every scene, top first. run calls it when the window closes so
+// scenes get to clean up however the game ends.
+func (s *scenes) close() {
+	for len(s.stack) > 0 {
+		top := s.stack[len(s.stack)-1]
+		s.stack = s.stack[:len(s.stack)-1]
+		top.exit()
+	}
+	s.pending = s.pending[:0]
+}
+
+func (s *scenes) empty() bool {
+	return len(s.stack) == 0
+}
+
+func (s *scenes) apply() {
+	for len(s.pending) > 0 {
+		t := s.pending[0]
+		s.pending = s.pending[1:]
+
+		switch t.kind {
+		case pushScene:
+			s.stack = append(s.stack, t.scene)
+			t.scene.enter()
+		case popScene:
+			if len(s.stack) > 0 {
+				top := s.stack[len(s.stack)-1]
+				s.stack = s.stack[:len(s.stack)-1]
+				top.exit()
+			}
+		case replaceScene:
+			for len(s.stack) > 0 {
+				top := s.stack[len(s.stack)-1]
+				s.stack = s.stack[:len(s.stack)-1]
+				top.exit()
+			}
+			s.stack = append(s.stack, t.scene)
+			t.scene.enter()
+		}
+	}
+}
+
+func (s *scenes) update(dt float64) {
+	s.apply()
+	if len(s.stack) > 0 {
+		s.stack[len(s.stack)-1].update(dt)
+	}
+	s.apply()
+}
+
+func (s *scenes) draw(win *pixelgl.Window) {
+	for _, sc := range s.stack {
+		sc.draw(win)
+	}
+}
@@ -0,0 +1,213 @@
+// Package scores keeps the local high-score table: the best games played on
+// this machine, with who played them, when, and on what difficulty.
+package scores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Size is how many entries the table keeps.
+const Size = 10
+
+// MaxInitials is how long a player's initials can be.
+const MaxInitials = 3
+
+// Entry is one game on the table.
+type Entry struct {
+	Initials string    `json:"initials"`
+	Score    int64     `json:"score"`
+	Date     time.Time `json:"date"`
+	Mode     string    `json:"mode"`
+}
+
+func (e Entry) valid() bool {
+	return e.Score > 0 && e.Initials != "" && len([]rune(e.Initials)) <= MaxInitials
+}
+
+// Table is the high scores, best first.
+type Table struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Dir is where the table is kept: the user's data directory, which on Linux
+// is $XDG_DATA_HOME, or ~/.local/share without it. Elsewhere it's the same as
+// the config directory.
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "pixelTest"), nil
+	}
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "plan9":
+	default:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "share", "pixelTest"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pixelTest"), nil
+}
+
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scores.json"), nil
+}
+
+// backup is where Save keeps the table it replaces.
+func backup(path string) string {
+	return path + ".bak"
+}
+
+// errCorrupt marks a table file that was read but made no sense.
+var errCorrupt = errors.New("high-score table is damaged")
+
+// Load reads the table at path. A missing file falls back to the backup Save
+// keeps, and failing that is an empty table. A damaged one falls back the
+// same way, and is moved aside to path.corrupt rather than overwritten; the
+// error says what happened, but the table returned is always usable. Entries
+// that make no sense are dropped.
+func Load(path string) (*Table, error) {
+	t, err := Read(path)
+	if err == nil {
+		if _, statErr := os.Stat(path); statErr == nil {
+			return t, nil
+		}
+		if t, bakErr := Read(backup(path)); bakErr == nil {
+			return t, nil
+		}
+		return &Table{}, nil
+	}
+	if !errors.Is(err, errCorrupt) {
+		return &Table{}, err
+	}
+	if _, statErr := os.Stat(backup(path)); statErr == nil {
+		if t, bakErr := Read(backup(path)); bakErr == nil {
+			os.Rename(path, path+".corrupt")
+			return t, fmt.Errorf("%s: %v; restored the previous table", path, err)
+		}
+	}
+	os.Rename(path, path+".corrupt")
+	return &Table{}, fmt.Errorf("%s: %v; starting a new table", path, err)
+}
+
+// Read reads the table at path as it is, without Load's recovery. A missing
+// file is an empty table.
+func Read(path string) (*Table, error) {
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Table{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	raw := &Table{}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", errCorrupt, err)
+	}
+	t := &Table{}
+	for _, e := range raw.Entries {
+		if e.valid() {
+			t.Entries = append(t.Entries, e)
+		}
+	}
+	t.sort()
+	return t, nil
+}
+
+// Save writes the table to path through a temporary file, keeping the table
+// it replaces as a backup for Load to fall back on.
+func (t *Table) Save(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	// The backup is a copy, so the table stays at path until the new one is
+	// renamed over it.
+	if old, err := ioutil.ReadFile(path); err == nil && json.Unmarshal(old, &Table{}) == nil {
+		if err := ioutil.WriteFile(backup(path)+".tmp", old, 0644); err == nil {
+			os.Rename(backup(path)+".tmp", backup(path))
+		}
+	}
+	return os.Rename(tmp, path)
+}
+
+func (t *Table) sort() {
+	// Ties go to whoever got there first.
+	sort.SliceStable(t.Entries, func(i, j int) bool {
+		a, b := t.Entries[i], t.Entries[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Date.Before(b.Date)
+	})
+	if len(t.Entries) > Size {
+		t.Entries = t.Entries[:Size]
+	}
+}
+
+// Qualifies reports whether score would make the table.
+func (t *Table) Qualifies(score int64) bool {
+	if score <= 0 {
+		return false
+	}
+	return len(t.Entries) < Size || score > t.Entries[len(t.Entries)-1].Score
+}
+
+// Add puts e on the table and returns its place, counting from zero, or -1
+// when it didn't make it.
+func (t *Table) Add(e Entry) int {
+	e.Initials = CleanInitials(e.Initials)
+	if !e.valid() || !t.Qualifies(e.Score) {
+		return -1
+	}
+	t.Entries = append(t.Entries, e)
+	t.sort()
+	for i := range t.Entries {
+		if t.Entries[i] == e {
+			return i
+		}
+	}
+	return -1
+}
+
+// CleanInitials upper-cases s and keeps its first MaxInitials letters and
+// digits.
+func CleanInitials(s string) string {
+	b := &strings.Builder{}
+	n := 0
+	for _, r := range strings.ToUpper(s) {
+		if n == MaxInitials {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			n++
+		}
+	}
+	return b.String()
+}
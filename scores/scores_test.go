@@ -0,0 +1,107 @@
+package scores
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func table(scores ...int64) *Table {
+	t := &Table{}
+	for i, score := range scores {
+		t.Add(Entry{Initials: "ABC", Score: score, Date: time.Date(2024, 1, 1, 0, i, 0, 0, time.UTC)})
+	}
+	return t
+}
+
+func best(t *testing.T, tab *Table) int64 {
+	t.Helper()
+	if len(tab.Entries) == 0 {
+		return 0
+	}
+	return tab.Entries[0].Score
+}
+
+func save(t *testing.T, tab *Table, path string) {
+	t.Helper()
+	if err := tab.Save(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSaveBacksUpTheTableItReplaces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	save(t, table(10), path)
+	save(t, table(20), path)
+
+	if got, err := Read(path); err != nil || best(t, got) != 20 {
+		t.Errorf("table best = %d, %v; want 20", best(t, got), err)
+	}
+	if got, err := Read(backup(path)); err != nil || best(t, got) != 10 {
+		t.Errorf("backup best = %d, %v; want 10", best(t, got), err)
+	}
+}
+
+func TestSaveKeepsBackupOverDamagedTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	save(t, table(10), path)
+	save(t, table(20), path)
+	if err := ioutil.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	save(t, table(30), path)
+
+	if got, err := Read(backup(path)); err != nil || best(t, got) != 10 {
+		t.Errorf("backup best = %d, %v; want 10", best(t, got), err)
+	}
+}
+
+func TestLoadMissingIsEmpty(t *testing.T) {
+	got, err := Load(filepath.Join(t.TempDir(), "scores.json"))
+	if err != nil || len(got.Entries) != 0 {
+		t.Errorf("Load = %v, %v; want an empty table", got.Entries, err)
+	}
+}
+
+func TestLoadFallsBackWhenMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	save(t, table(10), backup(path))
+
+	got, err := Load(path)
+	if err != nil || best(t, got) != 10 {
+		t.Errorf("Load best = %d, %v; want the backup's 10", best(t, got), err)
+	}
+}
+
+func TestLoadFallsBackWhenDamaged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	save(t, table(10), path)
+	save(t, table(20), path)
+	if err := ioutil.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(path)
+	if err == nil {
+		t.Error("Load of a damaged table gave no error")
+	}
+	if best(t, got) != 10 {
+		t.Errorf("Load best = %d, want the backup's 10", best(t, got))
+	}
+	if _, err := os.Stat(path + ".corrupt"); err != nil {
+		t.Errorf("damaged table wasn't moved aside: %v", err)
+	}
+}
+
+func TestLoadDamagedWithoutBackupIsEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	if err := ioutil.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err == nil || len(got.Entries) != 0 {
+		t.Errorf("Load = %v, %v; want an empty table and an error", got.Entries, err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/ui"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+type settingsScene struct {
+	app *app
+	ui  *ui.UI
+}
+
+func newSettingsScene(a *app) *settingsScene {
+	u := a.newUI()
+	menu := u.VStack(
+		u.Button(a.tr("menu.controls"), func() { a.scenes.push(newControlsScene(a)) }),
+		u.Button(a.tr("menu.display"), func() { a.scenes.push(newDisplayScene(a)) }),
+		u.Button(a.tr("menu.language"), func() { a.scenes.push(newLanguageScene(a)) }),
+		u.Button(a.tr("menu.back"), func() { a.scenes.pop() }),
+	)
+	menu.Stretch = true
+	u.Add(u.VStack(u.StyledLabel(a.tr("settings.title"), titleStyle), ui.NewSpace(0, 16), menu), hud.TopLeft, titleMargin)
+	return &settingsScene{app: a, ui: u}
+}
+
+func (s *settingsScene) enter() {}
+
+func (s *settingsScene) exit() {}
+
+func (s *settingsScene) update(dt float64) {
+	if s.app.actions.JustPressed(input.Back) {
+		s.app.scenes.pop()
+		return
+	}
+	s.ui.Update(s.app.uiInput(), s.app.world)
+}
+
+func (s *settingsScene) draw(win *pixelgl.Window) {
+	win.Clear(colornames.Mediumaquamarine)
+	s.ui.Draw(win, s.app.world)
+}
@@ -0,0 +1,117 @@
+package sim
+
+import "github.com/faiface/pixel"
+
+type Side int
+
+const (
+	PlayerSide Side = iota
+	EnemySide
+)
+
+// Position is where an entity is now and where it was before the last Step,
+// which the frontend interpolates between.
+type Position struct {
+	Pos  pixel.Vec
+	Prev pixel.Vec
+}
+
+func (p *Position) Lerp(alpha float64) pixel.Vec {
+	return pixel.Lerp(p.Prev, p.Pos, alpha)
+}
+
+// Velocity is in world units per second.
+type Velocity struct {
+	V pixel.Vec
+}
+
+// Sprite names the picture an entity is drawn with. Frame and Scale also give
+// its size for collisions.
+type Sprite struct {
+	Name  string
+	Frame pixel.Rect
+	Scale float64
+}
+
+type Faction struct {
+	Side Side
+}
+
+// Projectile marks missiles, as opposed to ships.
+type Projectile struct{}
+
+// Weapon fires missiles at random, Rate shots per second on average.
+type Weapon struct {
+	Rate float64
+}
+
+// Health is how much damage an entity can take before it's destroyed. Ships
+// without one, like those in saves from before there was health, go down to
+// any hit.
+type Health struct {
+	Current int
+	Max     int
+}
+
+// Damage is what an entity does to the other side's ships it hits: missiles
+// by striking them, ships by ramming. Entities without one do 1.
+type Damage struct {
+	Amount int
+}
+
+// Bounty is what the player scores for shooting an entity down.
+type Bounty struct {
+	Points int64
+}
+
+// Flash marks an entity that has just been hit, for Remaining seconds. A
+// ship that's flashing can't be rammed again.
+type Flash struct {
+	Remaining float64
+}
+
+// Pickup is a power-up waiting to be picked up.
+type Pickup struct {
+	Kind PowerUp
+}
+
+// Effects are the timed power-ups working on the player, as the seconds left
+// of each.
+type Effects struct {
+	Spread    float64
+	RapidFire float64
+	Shield    float64
+	// Reload is how many seconds rapid fire waits before its next shot.
+	Reload float64
+}
+
+// Left is how many seconds kind has left to run.
+func (fx *Effects) Left(kind PowerUp) float64 {
+	if t := fx.timer(kind); t != nil {
+		return *t
+	}
+	return 0
+}
+
+func (fx *Effects) timer(kind PowerUp) *float64 {
+	switch kind {
+	case Spread:
+		return &fx.Spread
+	case RapidFire:
+		return &fx.RapidFire
+	case Shield:
+		return &fx.Shield
+	}
+	return nil
+}
+
+// Invulnerable keeps an entity from being hit until Remaining seconds have
+// passed.
+type Invulnerable struct {
+	Remaining float64
+}
+
+// Lifetime despawns an entity once Remaining seconds have passed.
+type Lifetime struct {
+	Remaining float64
+}
@@ -0,0 +1,52 @@
+package sim
+
+import (
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/faiface/pixel"
+)
+
+func (g *Game) placeNewEnemy() ecs.Entity {
+	padding := g.Tuning.Padding
+	x, y := getCoordinates(g.Rand, padding+g.bounds.W(), padding, g.bounds.W()*2-padding, g.bounds.H()-padding)
+	return g.spawnEnemy(x, y, g.pickEnemyClass())
+}
+
+// pickEnemyClass chooses one of the tuning's enemy classes at random, by
+// weight. With only one to choose from it leaves the random numbers alone, so
+// tunings from before there were classes play out as they always did.
+func (g *Game) pickEnemyClass() EnemyClass {
+	classes := g.Tuning.enemyClasses()
+	if len(classes) == 1 {
+		return classes[0]
+	}
+	total := 0.0
+	for _, c := range classes {
+		total += c.Weight
+	}
+	r := g.Rand.Float64() * total
+	for _, c := range classes {
+		if r < c.Weight {
+			return c
+		}
+		r -= c.Weight
+	}
+	return classes[len(classes)-1]
+}
+
+func (g *Game) spawnEnemy(x float64, y float64, class EnemyClass) ecs.Entity {
+	t := g.Tuning
+	e := g.spawn(pixel.V(x, y), EnemySprite, t.EnemyScale*class.ScaleMul, EnemySide)
+	g.Velocities.Add(e, Velocity{V: pixel.V(-t.EnemySpeed*class.SpeedMul, 0)})
+	g.Weapons.Add(e, Weapon{Rate: t.EnemyFireRate * class.FireRateMul})
+	g.Healths.Add(e, Health{Current: class.Health, Max: class.Health})
+	g.Damages.Add(e, Damage{Amount: class.Damage})
+	g.Bounties.Add(e, Bounty{Points: class.Points})
+	return e
+}
+
+func isEnemyOffWorld(x, left float64) bool {
+	if x < left {
+		return true
+	}
+	return false
+}
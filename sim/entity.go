@@ -0,0 +1,79 @@
+package sim
+
+import (
+	"github.com/TheKaterTot/pixelTest/collide"
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/faiface/pixel"
+)
+
+const (
+	PlayerSprite  = "player"
+	EnemySprite   = "enemy"
+	MissileSprite = "missile"
+)
+
+func getInitialPos(frame pixel.Rect, scale, padding float64) pixel.Vec {
+	x, y := frame.Size().XY()
+	x = x * scale
+	y = y * scale
+	return pixel.V(x/2+padding, y/2+padding)
+}
+
+func getBounds(pos pixel.Vec, sprite *Sprite) pixel.Rect {
+	width := sprite.Frame.W() * sprite.Scale
+	height := sprite.Frame.H() * sprite.Scale
+	x := pos.X - (width / 2.0)
+	y := pos.Y - (height / 2.0)
+	return pixel.R(x, y, width+x, height+y)
+}
+
+// Bounds is the rectangle e's sprite covers.
+func (g *Game) Bounds(e ecs.Entity) pixel.Rect {
+	return getBounds(g.Positions.Get(e).Pos, g.Sprites.Get(e))
+}
+
+// Shape is e's hitbox where e is now.
+func (g *Game) Shape(e ecs.Entity) collide.Shape {
+	sprite := g.Sprites.Get(e)
+	return g.defs[sprite.Name].Hitbox.Place(sprite.Frame, g.Positions.Get(e).Pos, sprite.Scale)
+}
+
+func (g *Game) spawn(pos pixel.Vec, sprite string, scale float64, side Side) ecs.Entity {
+	e := g.World.Spawn()
+	g.Positions.Add(e, Position{Pos: pos, Prev: pos})
+	g.Sprites.Add(e, Sprite{Name: sprite, Frame: g.defs[sprite].Frame, Scale: scale})
+	g.Factions.Add(e, Faction{Side: side})
+	return e
+}
+
+func (g *Game) spawnPlayer() ecs.Entity {
+	scale := g.Tuning.PlayerScale
+	pos := getInitialPos(g.defs[PlayerSprite].Frame, scale, g.Tuning.Padding)
+	e := g.spawn(pos, PlayerSprite, scale, PlayerSide)
+	health := max(g.Tuning.PlayerHealth, 1)
+	g.Healths.Add(e, Health{Current: health, Max: health})
+	g.Damages.Add(e, Damage{Amount: max(g.Tuning.PlayerDamage, 1)})
+	return e
+}
+
+func (g *Game) spawnMissile(pos pixel.Vec, side Side) ecs.Entity {
+	t := g.Tuning
+	e := g.spawn(pos, MissileSprite, t.MissileScale, side)
+	g.Projectiles.Add(e, Projectile{})
+	if side == PlayerSide {
+		g.Velocities.Add(e, Velocity{V: pixel.V(t.MissileSpeed, 0)})
+		g.Damages.Add(e, Damage{Amount: max(t.MissileDamage, 1)})
+	} else {
+		g.Velocities.Add(e, Velocity{V: pixel.V(-t.MissileSpeed*t.EnemyMissileMul, 0)})
+		g.Lifetimes.Add(e, Lifetime{Remaining: t.EnemyMissileLifetime})
+		g.Damages.Add(e, Damage{Amount: max(t.EnemyMissileDamage, 1)})
+	}
+	return e
+}
+
+func isMissileOffWorld(x, right float64) bool {
+	if x > right {
+		return true
+	}
+	return false
+}
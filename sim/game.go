@@ -0,0 +1,144 @@
+// Package sim holds the game rules and world state. It knows nothing about
+// windows or OpenGL: the frontend feeds it world bounds, sprite definitions and an
+// Input snapshot per step, and draws whatever it finds in the state.
+package sim
+
+import (
+	"github.com/TheKaterTot/pixelTest/collide"
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/faiface/pixel"
+)
+
+// Tick is the fixed simulation timestep in seconds. Speeds are in world units
+// per second and fire chances in shots per second, so Step advances by Tick no
+// matter how fast the frontend renders.
+const Tick = 1.0 / 60
+
+// hashCellSize is a little over a ship's width.
+const hashCellSize = 128.0
+
+type Input struct {
+	Left  bool
+	Right bool
+	Up    bool
+	Down  bool
+	Fire  bool
+	// Firing is fire being held down, which rapid fire keeps firing on.
+	// Fire is only the press.
+	Firing bool
+}
+
+// Collision is the event recorded when A and B touch.
+type Collision struct {
+	A, B ecs.Entity
+}
+
+type Game struct {
+	Score   int64
+	Running bool
+	// Lives counts the player's lives, the one being played included.
+	Lives int
+	// ExtraLives is how many extra lives the score has earned so far.
+	ExtraLives int
+	Player     ecs.Entity
+	Seed       int64
+	Tuning     Tuning
+	// Rand is where every random decision in the game comes from.
+	Rand *RNG
+	// Collisions holds the collisions found in the latest Step.
+	Collisions []Collision
+
+	World         *ecs.World
+	Positions     *ecs.Store[Position]
+	Velocities    *ecs.Store[Velocity]
+	Sprites       *ecs.Store[Sprite]
+	Factions      *ecs.Store[Faction]
+	Projectiles   *ecs.Store[Projectile]
+	Weapons       *ecs.Store[Weapon]
+	Lifetimes     *ecs.Store[Lifetime]
+	Invulnerables *ecs.Store[Invulnerable]
+	Healths       *ecs.Store[Health]
+	Damages       *ecs.Store[Damage]
+	Bounties      *ecs.Store[Bounty]
+	Flashes       *ecs.Store[Flash]
+	Pickups       *ecs.Store[Pickup]
+	Effects       *ecs.Store[Effects]
+
+	in     Input
+	hash   *collide.Hash
+	bounds pixel.Rect
+	defs   map[string]SpriteDef
+}
+
+// SpriteDef is what the simulation needs to know about a picture: its frame,
+// which sizes the entities drawn with it, and its hitbox, nil for the whole
+// frame.
+type SpriteDef struct {
+	Frame  pixel.Rect
+	Hitbox *collide.Hitbox
+}
+
+// New starts a game inside bounds, with defs describing each sprite by name,
+// played by the numbers in t. Two games with the same tuning and seed, fed
+// the same inputs, play out identically.
+func New(bounds pixel.Rect, defs map[string]SpriteDef, t Tuning, seed int64) *Game {
+	g := newEmpty(bounds, defs, t, seed)
+	g.Player = g.spawnPlayer()
+	return g
+}
+
+// newEmpty sets up a game's stores and systems without putting anything in
+// the world.
+func newEmpty(bounds pixel.Rect, defs map[string]SpriteDef, t Tuning, seed int64) *Game {
+	w := ecs.NewWorld()
+	g := &Game{
+		Score:         int64(0),
+		Running:       true,
+		Lives:         max(t.Lives, 1),
+		Seed:          seed,
+		Tuning:        t,
+		Rand:          NewRNG(seed),
+		World:         w,
+		Positions:     ecs.NewStore[Position](w),
+		Velocities:    ecs.NewStore[Velocity](w),
+		Sprites:       ecs.NewStore[Sprite](w),
+		Factions:      ecs.NewStore[Faction](w),
+		Projectiles:   ecs.NewStore[Projectile](w),
+		Weapons:       ecs.NewStore[Weapon](w),
+		Lifetimes:     ecs.NewStore[Lifetime](w),
+		Invulnerables: ecs.NewStore[Invulnerable](w),
+		Healths:       ecs.NewStore[Health](w),
+		Damages:       ecs.NewStore[Damage](w),
+		Bounties:      ecs.NewStore[Bounty](w),
+		Flashes:       ecs.NewStore[Flash](w),
+		Pickups:       ecs.NewStore[Pickup](w),
+		Effects:       ecs.NewStore[Effects](w),
+		hash:          collide.NewHash(hashCellSize),
+		bounds:        bounds,
+		defs:          defs,
+	}
+
+	w.AddSystem(ecs.Update, "input", g.inputSystem)
+	w.AddSystem(ecs.Update, "collision", g.collisionSystem)
+	w.AddSystem(ecs.Update, "hit", g.hitSystem)
+	w.AddSystem(ecs.Update, "pickup", g.pickupSystem)
+	w.AddSystem(ecs.Update, "bounds", g.boundsSystem)
+	w.AddSystem(ecs.Update, "extra lives", g.extraLifeSystem)
+	w.AddSystem(ecs.Update, "spawn", g.spawnSystem)
+	w.AddSystem(ecs.Update, "movement", g.movementSystem)
+	w.AddSystem(ecs.Update, "weapons", g.weaponSystem)
+	w.AddSystem(ecs.Update, "lifetime", g.lifetimeSystem)
+	w.AddSystem(ecs.Update, "invulnerability", g.invulnerabilitySystem)
+	w.AddSystem(ecs.Update, "flash", g.flashSystem)
+	w.AddSystem(ecs.Update, "effects", g.effectsSystem)
+	return g
+}
+
+// Step advances the game by one Tick.
+func (g *Game) Step(in Input) {
+	g.Positions.Each(func(e ecs.Entity, p *Position) {
+		p.Prev = p.Pos
+	})
+	g.in = in
+	g.World.Run(ecs.Update, Tick)
+}
@@ -0,0 +1,213 @@
+package sim
+
+import (
+	"math"
+	"testing"
+
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/faiface/pixel"
+)
+
+var (
+	testBounds = pixel.R(0, 0, 1024, 768)
+	testDefs   = map[string]SpriteDef{
+		PlayerSprite:  {Frame: pixel.R(0, 0, 800, 600)},
+		EnemySprite:   {Frame: pixel.R(0, 0, 800, 600)},
+		MissileSprite: {Frame: pixel.R(0, 0, 600, 300)},
+	}
+)
+
+// quietTuning is the default tuning with no enemies coming on their own, so
+// a test decides everything that's in the world.
+func quietTuning() Tuning {
+	t := DefaultTuning()
+	t.MaxEnemies = 0
+	return t
+}
+
+func near(a, b pixel.Vec) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestPlayerMovesPerTick(t *testing.T) {
+	tuning := quietTuning()
+	g := New(testBounds, testDefs, tuning, 1)
+	start := g.Positions.Get(g.Player).Pos
+
+	g.Step(Input{Right: true, Up: true})
+	want := start.Add(pixel.V(1, 1).Scaled(tuning.PlayerSpeed * Tick))
+	if p := g.Positions.Get(g.Player); !near(p.Pos, want) || p.Prev != start {
+		t.Errorf("got %v from %v, want %v from %v", p.Pos, p.Prev, want, start)
+	}
+
+	// Holding still for a second leaves the player where it was.
+	for i := 0; i < int(1/Tick); i++ {
+		g.Step(Input{})
+	}
+	if p := g.Positions.Get(g.Player).Pos; !near(p, want) {
+		t.Errorf("player drifted to %v", p)
+	}
+}
+
+func TestEnemyMovesPerTick(t *testing.T) {
+	tuning := quietTuning()
+	tuning.EnemyFireRate = 0
+	g := New(testBounds, testDefs, tuning, 1)
+	class := tuning.Enemies[0]
+	e := g.spawnEnemy(800, 400, class)
+
+	steps := int(1 / Tick)
+	for i := 0; i < steps; i++ {
+		g.Step(Input{})
+	}
+	want := pixel.V(800-tuning.EnemySpeed*class.SpeedMul*Tick*float64(steps), 400)
+	if p := g.Positions.Get(e).Pos; !near(p, want) {
+		t.Errorf("enemy at %v after a second, want %v", p, want)
+	}
+}
+
+func TestEnemyReachingHarborCostsLife(t *testing.T) {
+	tuning := quietTuning()
+	tuning.Lives = 2
+	g := New(testBounds, testDefs, tuning, 1)
+
+	g.spawnEnemy(testBounds.Min.X-1, 600, tuning.Enemies[0])
+	g.Step(Input{})
+	if g.Lives != 1 || !g.Running {
+		t.Fatalf("got %d lives, running %v; want 1 and still running", g.Lives, g.Running)
+	}
+	if n := g.enemyShips(); n != 0 {
+		t.Errorf("%d enemy ships left after reaching the harbor", n)
+	}
+
+	g.spawnEnemy(testBounds.Min.X-1, 600, tuning.Enemies[0])
+	g.Step(Input{})
+	if g.Lives != 0 || g.Running {
+		t.Errorf("got %d lives, running %v; want the game over", g.Lives, g.Running)
+	}
+}
+
+func TestPlayerDies(t *testing.T) {
+	tuning := quietTuning()
+	tuning.Lives = 2
+	tuning.PlayerHealth = 1
+	g := New(testBounds, testDefs, tuning, 1)
+	start := g.Positions.Get(g.Player).Pos
+
+	g.Step(Input{Up: true})
+	g.spawnMissile(g.Positions.Get(g.Player).Pos, EnemySide)
+	g.Step(Input{})
+	if g.Lives != 1 || !g.Running {
+		t.Fatalf("got %d lives, running %v; want 1 and still running", g.Lives, g.Running)
+	}
+	if p := g.Positions.Get(g.Player).Pos; !near(p, start) {
+		t.Errorf("player respawned at %v, want %v", p, start)
+	}
+	if !g.Invulnerables.Has(g.Player) {
+		t.Error("respawned player isn't invulnerable")
+	}
+
+	// An invulnerable player can't be hit.
+	g.spawnMissile(g.Positions.Get(g.Player).Pos, EnemySide)
+	g.Step(Input{})
+	if g.Lives != 1 {
+		t.Fatalf("invulnerable player lost a life")
+	}
+
+	for g.Invulnerables.Has(g.Player) {
+		g.Step(Input{})
+	}
+	g.spawnMissile(g.Positions.Get(g.Player).Pos, EnemySide)
+	g.Step(Input{})
+	if g.Lives != 0 || g.Running {
+		t.Errorf("got %d lives, running %v; want the game over", g.Lives, g.Running)
+	}
+}
+
+func TestSameSeedSameGame(t *testing.T) {
+	play := func() (int64, int, uint64) {
+		g := New(testBounds, testDefs, DefaultTuning(), 7)
+		rng := NewRNG(3)
+		for i := 0; i < 60*60 && g.Running; i++ {
+			g.Step(Input{Up: rng.Float64() < 0.3, Down: rng.Float64() < 0.3, Fire: rng.Float64() < 0.1})
+		}
+		return g.Score, g.Lives, g.Rand.State
+	}
+	score, lives, state := play()
+	score2, lives2, state2 := play()
+	if score != score2 || lives != lives2 || state != state2 {
+		t.Errorf("same seed played out differently: %d/%d/%x vs %d/%d/%x", score, lives, state, score2, lives2, state2)
+	}
+}
+
+// sturdy is an enemy class that takes three hits.
+var sturdy = EnemyClass{Weight: 1, Health: 3, Damage: 1, Points: 1, SpeedMul: 1, FireRateMul: 1, ScaleMul: 1}
+
+func TestSpentMissileDoesNoMoreDamage(t *testing.T) {
+	tuning := quietTuning()
+	tuning.EnemyFireRate = 0
+	// Collisions come in entity order, so try the missile on both sides of
+	// the ships.
+	for _, missileFirst := range []bool{false, true} {
+		g := New(testBounds, testDefs, tuning, 1)
+		var m ecs.Entity
+		if missileFirst {
+			m = g.spawnMissile(pixel.V(600, 400), PlayerSide)
+		}
+		a := g.spawnEnemy(600, 400, sturdy)
+		b := g.spawnEnemy(600, 400, sturdy)
+		if !missileFirst {
+			m = g.spawnMissile(pixel.V(600, 400), PlayerSide)
+		}
+		g.Step(Input{})
+
+		hurt := 0
+		for _, e := range []ecs.Entity{a, b} {
+			if h := g.Healths.Get(e); h.Current < h.Max {
+				hurt++
+			}
+		}
+		if hurt != 1 {
+			t.Errorf("missile first %v: one missile damaged %d ships, want 1", missileFirst, hurt)
+		}
+		if g.World.Alive(m) {
+			t.Errorf("missile first %v: the missile outlived its hit", missileFirst)
+		}
+	}
+}
+
+func TestMissileHitsShipSpawnedAfterIt(t *testing.T) {
+	tuning := quietTuning()
+	tuning.EnemyFireRate = 0
+	g := New(testBounds, testDefs, tuning, 1)
+	m := g.spawnMissile(pixel.V(600, 400), PlayerSide)
+	e := g.spawnEnemy(600, 400, sturdy)
+	g.Step(Input{})
+
+	if h := g.Healths.Get(e); h.Current != h.Max-1 {
+		t.Errorf("ship has %d/%d health, want %d", h.Current, h.Max, h.Max-1)
+	}
+	if g.World.Alive(m) {
+		t.Error("the missile outlived its hit")
+	}
+}
+
+func TestRammingWaitsForFlash(t *testing.T) {
+	tuning := quietTuning()
+	tuning.EnemyFireRate = 0
+	tuning.PlayerHealth = 100
+	g := New(testBounds, testDefs, tuning, 1)
+	sturdy := EnemyClass{Weight: 1, Health: 100, Damage: 1, Points: 1, SpeedMul: 0.01, FireRateMul: 1, ScaleMul: 1}
+	e := g.spawnEnemy(0, 0, sturdy)
+	p := g.Positions.Get(g.Player).Pos
+	*g.Positions.Get(e) = Position{Pos: p, Prev: p}
+
+	ticks := 10
+	for i := 0; i < ticks; i++ {
+		g.Step(Input{})
+	}
+	rams := int(math.Ceil(float64(ticks) * Tick / tuning.HitFlash))
+	if h := g.Healths.Get(g.Player); h.Max-h.Current > rams {
+		t.Errorf("player took %d damage in %d ticks of ramming, want at most %d", h.Max-h.Current, ticks, rams)
+	}
+}
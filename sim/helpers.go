@@ -0,0 +1,9 @@
+package sim
+
+func getCoordinates(rng *RNG, llx, lly, trx, try float64) (float64, float64) {
+	a := trx - llx
+	b := try - lly
+	x := rng.Float64()*a + llx
+	y := rng.Float64()*b + lly
+	return x, y
+}
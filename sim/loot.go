@@ -0,0 +1,121 @@
+package sim
+
+import (
+	"math"
+
+	"github.com/TheKaterTot/pixelTest/collide"
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/faiface/pixel"
+)
+
+// dropLoot rolls the loot table for a ship shot down at pos. With nothing on
+// the table it leaves the random numbers alone, so tunings from before there
+// was loot play out as they always did.
+func (g *Game) dropLoot(pos pixel.Vec) {
+	drops := g.Tuning.Loot.Drops
+	if len(drops) == 0 {
+		return
+	}
+	r := g.Rand.Float64()
+	for _, d := range drops {
+		if r < d.Chance {
+			g.spawnPickup(pos, d.Kind)
+			return
+		}
+		r -= d.Chance
+	}
+}
+
+func (g *Game) spawnPickup(pos pixel.Vec, kind PowerUp) ecs.Entity {
+	l := g.Tuning.Loot
+	e := g.World.Spawn()
+	g.Positions.Add(e, Position{Pos: pos, Prev: pos})
+	g.Velocities.Add(e, Velocity{V: pixel.V(-l.Drift, 0)})
+	g.Lifetimes.Add(e, Lifetime{Remaining: l.Lifetime})
+	g.Pickups.Add(e, Pickup{Kind: kind})
+	return e
+}
+
+// pickupSystem hands the player whatever pickups they touch.
+func (g *Game) pickupSystem(dt float64) {
+	player := g.Shape(g.Player)
+	g.Pickups.Each(func(e ecs.Entity, p *Pickup) {
+		if !g.World.Alive(e) {
+			return
+		}
+		if collide.Collide(collide.Circle{Center: g.Positions.Get(e).Pos, Radius: g.Tuning.Loot.Radius}, player) {
+			g.collect(p.Kind)
+			g.World.Despawn(e)
+		}
+	})
+}
+
+// collect gives the player a power-up. Picking up one that's still running
+// starts it over.
+func (g *Game) collect(kind PowerUp) {
+	if kind == ExtraLife {
+		g.Lives++
+		return
+	}
+	fx := g.Effects.Get(g.Player)
+	if fx == nil {
+		fx = g.Effects.Add(g.Player, Effects{})
+	}
+	if t := fx.timer(kind); t != nil {
+		*t = g.Tuning.Loot.Duration(kind)
+	}
+}
+
+// Shielded reports whether e's shield is up.
+func (g *Game) Shielded(e ecs.Entity) bool {
+	fx := g.Effects.Get(e)
+	return fx != nil && fx.Shield > 0
+}
+
+// playerFire fires the player's missiles: one, or a fan of three with spread
+// shot.
+func (g *Game) playerFire(pos pixel.Vec) {
+	g.spawnMissile(pos, PlayerSide)
+	if fx := g.Effects.Get(g.Player); fx == nil || fx.Spread <= 0 {
+		return
+	}
+	angle := g.Tuning.Loot.SpreadAngle * math.Pi / 180
+	for _, turn := range []float64{angle, -angle} {
+		v := g.Velocities.Get(g.spawnMissile(pos, PlayerSide))
+		v.V = v.V.Rotated(turn)
+	}
+}
+
+// rapidFire keeps firing while fire is held under rapid fire.
+func (g *Game) rapidFire(pos pixel.Vec, dt float64) {
+	fx := g.Effects.Get(g.Player)
+	rate := g.Tuning.Loot.RapidFireRate
+	if fx == nil || fx.RapidFire <= 0 || rate <= 0 {
+		return
+	}
+	switch {
+	case g.in.Fire:
+		fx.Reload = 1 / rate
+	case g.in.Firing:
+		if fx.Reload -= dt; fx.Reload <= 0 {
+			g.playerFire(pos)
+			fx.Reload = 1 / rate
+		}
+	}
+}
+
+// effectsSystem runs the player's power-ups down, and drops them once
+// they've all worn off.
+func (g *Game) effectsSystem(dt float64) {
+	g.Effects.Each(func(e ecs.Entity, fx *Effects) {
+		running := false
+		for _, kind := range Timed {
+			t := fx.timer(kind)
+			*t = math.Max(*t-dt, 0)
+			running = running || *t > 0
+		}
+		if !running {
+			g.Effects.Remove(e)
+		}
+	})
+}
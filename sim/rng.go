@@ -0,0 +1,33 @@
+package sim
+
+// RNG is the game's only source of randomness. It's a xorshift64* generator
+// whose whole state is one exported number, so it can be saved and restored
+// along with the rest of the game and a seed always plays out the same way.
+type RNG struct {
+	State uint64
+}
+
+// NewRNG seeds an RNG. The seed is mixed with splitmix64 first, so nearby
+// seeds don't start out looking alike and zero is a fine seed.
+func NewRNG(seed int64) *RNG {
+	z := uint64(seed) + 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z = z ^ (z >> 31)
+	if z == 0 {
+		z = 1
+	}
+	return &RNG{State: z}
+}
+
+func (r *RNG) Uint64() uint64 {
+	r.State ^= r.State >> 12
+	r.State ^= r.State << 25
+	r.State ^= r.State >> 27
+	return r.State * 0x2545f4914f6cdd1d
+}
+
+// Float64 returns a number in [0, 1).
+func (r *RNG) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
@@ -0,0 +1,180 @@
+package sim
+
+import (
+	"fmt"
+
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/faiface/pixel"
+)
+
+// SaveVersion is bumped whenever SaveState changes shape. Restore refuses
+// states from other versions rather than guess at them, except the older ones
+// back to 2: from before lives, a game plays on with the one life it had,
+// from before health its ships go down to any hit, and from before loot it
+// has none.
+const SaveVersion = 5
+
+// SaveState is everything needed to carry on a game exactly where it was,
+// laid out for encoding. Sprites are kept by asset name; their frames and
+// hitboxes come from whatever SpriteDefs the game is restored with.
+type SaveState struct {
+	Version    int
+	Bounds     pixel.Rect
+	Seed       int64
+	Tuning     Tuning
+	Rand       uint64
+	Score      int64
+	Running    bool
+	Lives      int
+	ExtraLives int
+	Player     ecs.Entity
+	Next       ecs.Entity
+	Entities   []SavedEntity
+}
+
+// SavedEntity is one entity with whichever components it has.
+type SavedEntity struct {
+	ID           ecs.Entity
+	Position     *Position     `json:",omitempty"`
+	Velocity     *Velocity     `json:",omitempty"`
+	Sprite       *SavedSprite  `json:",omitempty"`
+	Faction      *Faction      `json:",omitempty"`
+	Projectile   bool          `json:",omitempty"`
+	Weapon       *Weapon       `json:",omitempty"`
+	Lifetime     *Lifetime     `json:",omitempty"`
+	Invulnerable *Invulnerable `json:",omitempty"`
+	Health       *Health       `json:",omitempty"`
+	Damage       *Damage       `json:",omitempty"`
+	Bounty       *Bounty       `json:",omitempty"`
+	Flash        *Flash        `json:",omitempty"`
+	Pickup       *Pickup       `json:",omitempty"`
+	Effects      *Effects      `json:",omitempty"`
+}
+
+type SavedSprite struct {
+	Name  string
+	Scale float64
+}
+
+func copyOf[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+func (g *Game) State() *SaveState {
+	g.World.Flush()
+	s := &SaveState{
+		Version:    SaveVersion,
+		Bounds:     g.bounds,
+		Seed:       g.Seed,
+		Tuning:     g.Tuning,
+		Rand:       g.Rand.State,
+		Score:      g.Score,
+		Running:    g.Running,
+		Lives:      g.Lives,
+		ExtraLives: g.ExtraLives,
+		Player:     g.Player,
+		Next:       g.World.Next(),
+	}
+
+	for _, e := range g.World.Entities() {
+		saved := SavedEntity{
+			ID:           e,
+			Position:     copyOf(g.Positions.Get(e)),
+			Velocity:     copyOf(g.Velocities.Get(e)),
+			Faction:      copyOf(g.Factions.Get(e)),
+			Projectile:   g.Projectiles.Has(e),
+			Weapon:       copyOf(g.Weapons.Get(e)),
+			Lifetime:     copyOf(g.Lifetimes.Get(e)),
+			Invulnerable: copyOf(g.Invulnerables.Get(e)),
+			Health:       copyOf(g.Healths.Get(e)),
+			Damage:       copyOf(g.Damages.Get(e)),
+			Bounty:       copyOf(g.Bounties.Get(e)),
+			Flash:        copyOf(g.Flashes.Get(e)),
+			Pickup:       copyOf(g.Pickups.Get(e)),
+			Effects:      copyOf(g.Effects.Get(e)),
+		}
+		if sprite := g.Sprites.Get(e); sprite != nil {
+			saved.Sprite = &SavedSprite{Name: sprite.Name, Scale: sprite.Scale}
+		}
+		s.Entities = append(s.Entities, saved)
+	}
+	return s
+}
+
+// Restore rebuilds a game from a SaveState. The result steps on exactly as
+// the saved game would have, since it keeps the tuning it was saved with.
+func Restore(s *SaveState, defs map[string]SpriteDef) (*Game, error) {
+	if s.Version < 2 || s.Version > SaveVersion {
+		return nil, fmt.Errorf("save version %d, want %d", s.Version, SaveVersion)
+	}
+
+	g := newEmpty(s.Bounds, defs, s.Tuning, s.Seed)
+	g.Rand = &RNG{State: s.Rand}
+	g.Score = s.Score
+	g.Running = s.Running
+	g.Lives = max(s.Lives, 1)
+	g.ExtraLives = s.ExtraLives
+	g.Player = s.Player
+
+	ids := []ecs.Entity{}
+	for _, saved := range s.Entities {
+		e := saved.ID
+		ids = append(ids, e)
+		if saved.Position != nil {
+			g.Positions.Add(e, *saved.Position)
+		}
+		if saved.Velocity != nil {
+			g.Velocities.Add(e, *saved.Velocity)
+		}
+		if saved.Sprite != nil {
+			def, ok := defs[saved.Sprite.Name]
+			if !ok {
+				return nil, fmt.Errorf("entity %d: unknown sprite %q", e, saved.Sprite.Name)
+			}
+			g.Sprites.Add(e, Sprite{Name: saved.Sprite.Name, Frame: def.Frame, Scale: saved.Sprite.Scale})
+		}
+		if saved.Faction != nil {
+			g.Factions.Add(e, *saved.Faction)
+		}
+		if saved.Projectile {
+			g.Projectiles.Add(e, Projectile{})
+		}
+		if saved.Weapon != nil {
+			g.Weapons.Add(e, *saved.Weapon)
+		}
+		if saved.Lifetime != nil {
+			g.Lifetimes.Add(e, *saved.Lifetime)
+		}
+		if saved.Invulnerable != nil {
+			g.Invulnerables.Add(e, *saved.Invulnerable)
+		}
+		if saved.Health != nil {
+			g.Healths.Add(e, *saved.Health)
+		}
+		if saved.Damage != nil {
+			g.Damages.Add(e, *saved.Damage)
+		}
+		if saved.Bounty != nil {
+			g.Bounties.Add(e, *saved.Bounty)
+		}
+		if saved.Flash != nil {
+			g.Flashes.Add(e, *saved.Flash)
+		}
+		if saved.Pickup != nil {
+			g.Pickups.Add(e, *saved.Pickup)
+		}
+		if saved.Effects != nil {
+			g.Effects.Add(e, *saved.Effects)
+		}
+	}
+	g.World.Restore(ids, s.Next)
+
+	if !g.Positions.Has(g.Player) || !g.Sprites.Has(g.Player) {
+		return nil, fmt.Errorf("player entity %d is missing", g.Player)
+	}
+	return g, nil
+}
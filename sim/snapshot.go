@@ -0,0 +1,31 @@
+package sim
+
+// Clone returns an independent copy of the game. Stepping either one leaves
+// the other alone, which makes a clone a snapshot the game can be rewound to.
+func (g *Game) Clone() *Game {
+	c := newEmpty(g.bounds, g.defs, g.Tuning, g.Seed)
+	c.Score = g.Score
+	c.Running = g.Running
+	c.Lives = g.Lives
+	c.ExtraLives = g.ExtraLives
+	c.Player = g.Player
+	c.Rand = &RNG{State: g.Rand.State}
+	c.Collisions = append([]Collision(nil), g.Collisions...)
+
+	c.World.CopyFrom(g.World)
+	c.Positions.CopyFrom(g.Positions)
+	c.Velocities.CopyFrom(g.Velocities)
+	c.Sprites.CopyFrom(g.Sprites)
+	c.Factions.CopyFrom(g.Factions)
+	c.Projectiles.CopyFrom(g.Projectiles)
+	c.Weapons.CopyFrom(g.Weapons)
+	c.Lifetimes.CopyFrom(g.Lifetimes)
+	c.Invulnerables.CopyFrom(g.Invulnerables)
+	c.Healths.CopyFrom(g.Healths)
+	c.Damages.CopyFrom(g.Damages)
+	c.Bounties.CopyFrom(g.Bounties)
+	c.Flashes.CopyFrom(g.Flashes)
+	c.Pickups.CopyFrom(g.Pickups)
+	c.Effects.CopyFrom(g.Effects)
+	return c
+}
@@ -0,0 +1,255 @@
+package sim
+
+import (
+	"github.com/TheKaterTot/pixelTest/collide"
+	"github.com/TheKaterTot/pixelTest/ecs"
+	"github.com/faiface/pixel"
+)
+
+func (g *Game) inputSystem(dt float64) {
+	player := g.Positions.Get(g.Player)
+	padding := g.Tuning.Padding
+	speed := g.Tuning.PlayerSpeed * dt
+	ctrl := pixel.ZV
+
+	if g.in.Right && player.Pos.X < (g.bounds.W()-padding) {
+		ctrl.X += speed
+	}
+	if g.in.Left && player.Pos.X > padding {
+		ctrl.X -= speed
+	}
+
+	if g.in.Up && player.Pos.Y < (g.bounds.H()-padding) {
+		ctrl.Y += speed
+	}
+
+	if g.in.Down && player.Pos.Y > padding {
+		ctrl.Y -= speed
+	}
+
+	player.Pos = ctrl.Add(player.Pos)
+
+	if g.in.Fire {
+		g.playerFire(player.Pos)
+	}
+	g.rapidFire(player.Pos, dt)
+}
+
+// collisionSystem finds every pair of touching entities and records a
+// Collision for each, once per pair. The hash narrows things down by bounding
+// box and the hitboxes decide.
+func (g *Game) collisionSystem(dt float64) {
+	g.Collisions = g.Collisions[:0]
+	g.hash.Clear()
+	shapes := map[ecs.Entity]collide.Shape{}
+	g.Factions.Each(func(e ecs.Entity, _ *Faction) {
+		shapes[e] = g.Shape(e)
+		g.hash.Insert(e, shapes[e].Bounds())
+	})
+	for _, pair := range g.hash.Pairs() {
+		if collide.Collide(shapes[pair.A], shapes[pair.B]) {
+			g.Collisions = append(g.Collisions, Collision{A: pair.A, B: pair.B})
+		}
+	}
+}
+
+// hitSystem resolves each collision once, in the order the collisions were
+// found. Collisions with anything invulnerable don't count, so a player who
+// has just lost a life can't lose another to the same crash. A missile is
+// spent on the first ship it touches, and spent records which have been, so
+// none hits twice in a step.
+func (g *Game) hitSystem(dt float64) {
+	spent := map[ecs.Entity]bool{}
+	for _, c := range g.Collisions {
+		if g.Invulnerables.Has(c.A) || g.Invulnerables.Has(c.B) {
+			continue
+		}
+		if g.Factions.Get(c.A).Side == g.Factions.Get(c.B).Side {
+			continue
+		}
+
+		// Missiles pass each other by.
+		switch a, b := g.Projectiles.Has(c.A), g.Projectiles.Has(c.B); {
+		case a && b:
+		case a:
+			g.shoot(c.B, c.A, spent)
+		case b:
+			g.shoot(c.A, c.B, spent)
+		default:
+			g.ram(c.A, c.B)
+			g.ram(c.B, c.A)
+		}
+	}
+}
+
+// shoot spends missile on ship, doing its damage first. A ship destroyed
+// earlier in the step still stops the missile, but takes no more damage.
+func (g *Game) shoot(ship, missile ecs.Entity, spent map[ecs.Entity]bool) {
+	if spent[missile] {
+		return
+	}
+	spent[missile] = true
+	if g.World.Alive(ship) {
+		g.damage(ship, missile, true)
+	}
+	g.World.Despawn(missile)
+}
+
+// ram does other's damage to e when two ships touch, unless e is still
+// flashing from the last hit or either was destroyed earlier in the step.
+func (g *Game) ram(e, other ecs.Entity) {
+	if g.Flashes.Has(e) || !g.World.Alive(e) || !g.World.Alive(other) {
+		return
+	}
+	g.damage(e, other, false)
+}
+
+// damage does other's damage to e, unless e's shield is up. Enemy ships that
+// run out of health are destroyed, and score their bounty and maybe drop loot
+// if shot down; the player loses a life.
+func (g *Game) damage(e, other ecs.Entity, shot bool) {
+	amount := 1
+	if d := g.Damages.Get(other); d != nil {
+		amount = d.Amount
+	}
+	if g.Shielded(e) {
+		return
+	}
+	h := g.Healths.Get(e)
+	if h == nil {
+		h = &Health{Current: 1, Max: 1}
+	}
+	if h.Current -= amount; h.Current > 0 {
+		if g.Tuning.HitFlash > 0 {
+			g.Flashes.Add(e, Flash{Remaining: g.Tuning.HitFlash})
+		}
+		return
+	}
+
+	if e == g.Player {
+		g.die()
+		return
+	}
+	g.World.Despawn(e)
+	if shot {
+		g.Score += g.bounty(e)
+		g.dropLoot(g.Positions.Get(e).Pos)
+	}
+}
+
+// bounty is what shooting e down scores. Ships from before there were
+// bounties score 1.
+func (g *Game) bounty(e ecs.Entity) int64 {
+	if b := g.Bounties.Get(e); b != nil {
+		return b.Points
+	}
+	return 1
+}
+
+// boundsSystem drops the player's missiles once they leave the world, and
+// takes a life when an enemy ship gets past the player into the harbor.
+func (g *Game) boundsSystem(dt float64) {
+	g.Factions.Each(func(e ecs.Entity, f *Faction) {
+		x := g.Positions.Get(e).Pos.X
+		switch {
+		case f.Side == PlayerSide && g.Projectiles.Has(e) && isMissileOffWorld(x, g.bounds.Max.X):
+			g.World.Despawn(e)
+		case f.Side == EnemySide && !g.Projectiles.Has(e) && isEnemyOffWorld(x, g.bounds.Min.X):
+			g.loseLife()
+			g.World.Despawn(e)
+		}
+	})
+}
+
+// loseLife takes one of the player's lives, and ends the game with the last.
+func (g *Game) loseLife() {
+	g.Lives--
+	if g.Lives <= 0 {
+		g.Running = false
+	}
+}
+
+// die is the player running out of health: it costs a life, and if there are
+// any left the player starts again where the game began, good as new but
+// without power-ups, and invulnerable for a while.
+func (g *Game) die() {
+	g.loseLife()
+	if !g.Running {
+		return
+	}
+	pos := getInitialPos(g.defs[PlayerSprite].Frame, g.Tuning.PlayerScale, g.Tuning.Padding)
+	*g.Positions.Get(g.Player) = Position{Pos: pos, Prev: pos}
+	if h := g.Healths.Get(g.Player); h != nil {
+		h.Current = h.Max
+	}
+	g.Flashes.Remove(g.Player)
+	g.Effects.Remove(g.Player)
+	if g.Tuning.Invulnerable > 0 {
+		g.Invulnerables.Add(g.Player, Invulnerable{Remaining: g.Tuning.Invulnerable})
+	}
+}
+
+// extraLifeSystem hands out the lives the score has earned.
+func (g *Game) extraLifeSystem(dt float64) {
+	for earned := g.Tuning.ExtraLivesAt(g.Score); g.ExtraLives < earned; g.ExtraLives++ {
+		g.Lives++
+	}
+}
+
+func (g *Game) enemyShips() int {
+	n := 0
+	g.Factions.Each(func(e ecs.Entity, f *Faction) {
+		if f.Side == EnemySide && !g.Projectiles.Has(e) {
+			n++
+		}
+	})
+	return n
+}
+
+func (g *Game) spawnSystem(dt float64) {
+	for i := g.enemyShips(); i < g.Tuning.MaxEnemies; i++ {
+		g.placeNewEnemy()
+	}
+}
+
+func (g *Game) movementSystem(dt float64) {
+	g.Velocities.Each(func(e ecs.Entity, v *Velocity) {
+		p := g.Positions.Get(e)
+		p.Pos = p.Pos.Add(v.V.Scaled(dt))
+	})
+}
+
+func (g *Game) weaponSystem(dt float64) {
+	g.Weapons.Each(func(e ecs.Entity, w *Weapon) {
+		if g.Rand.Float64() < w.Rate*dt {
+			g.spawnMissile(g.Positions.Get(e).Pos, g.Factions.Get(e).Side)
+		}
+	})
+}
+
+func (g *Game) invulnerabilitySystem(dt float64) {
+	g.Invulnerables.Each(func(e ecs.Entity, inv *Invulnerable) {
+		inv.Remaining -= dt
+		if inv.Remaining <= 0 {
+			g.Invulnerables.Remove(e)
+		}
+	})
+}
+
+func (g *Game) flashSystem(dt float64) {
+	g.Flashes.Each(func(e ecs.Entity, f *Flash) {
+		f.Remaining -= dt
+		if f.Remaining <= 0 {
+			g.Flashes.Remove(e)
+		}
+	})
+}
+
+func (g *Game) lifetimeSystem(dt float64) {
+	g.Lifetimes.Each(func(e ecs.Entity, l *Lifetime) {
+		l.Remaining -= dt
+		if l.Remaining <= 0 {
+			g.World.Despawn(e)
+		}
+	})
+}
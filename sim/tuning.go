@@ -0,0 +1,199 @@
+package sim
+
+// Tuning is the set of numbers that decide how the game plays. Speeds are in
+// world units per second, fire rates in shots per second.
+type Tuning struct {
+	// Padding keeps the player, and newly placed enemies, this far inside
+	// the edges of the world.
+	Padding float64 `json:"padding"`
+
+	PlayerSpeed  float64 `json:"player_speed"`
+	EnemySpeed   float64 `json:"enemy_speed"`
+	MissileSpeed float64 `json:"missile_speed"`
+
+	// EnemyFireRate is how often, on average, each enemy ship fires.
+	EnemyFireRate float64 `json:"enemy_fire_rate"`
+	// EnemyMissileMul is how much faster enemy missiles fly than the
+	// player's.
+	EnemyMissileMul float64 `json:"enemy_missile_mul"`
+	// EnemyMissileLifetime is how many seconds an enemy missile lasts.
+	EnemyMissileLifetime float64 `json:"enemy_missile_lifetime"`
+	// MaxEnemies is how many enemy ships are kept on the way at once.
+	MaxEnemies int `json:"max_enemies"`
+
+	PlayerScale  float64 `json:"player_scale"`
+	EnemyScale   float64 `json:"enemy_scale"`
+	MissileScale float64 `json:"missile_scale"`
+
+	// Lives is how many lives the player starts with. Tunings from before
+	// there were lives have 0, which plays as 1.
+	Lives int `json:"lives"`
+	// ExtraLives are the scores, lowest first, at which the player earns
+	// another life. After the last of them, ExtraLifeEvery, when it isn't 0,
+	// earns one every that many points.
+	ExtraLives     []int64 `json:"extra_lives"`
+	ExtraLifeEvery int64   `json:"extra_life_every"`
+	// Invulnerable is how many seconds a respawned player can't be hit for.
+	Invulnerable float64 `json:"invulnerable"`
+
+	// PlayerHealth is how much damage costs the player a life. PlayerDamage
+	// is what the player does to a ship by ramming it, and MissileDamage and
+	// EnemyMissileDamage what each side's missiles do. Tunings from before
+	// there was health have 0 for all of them, which plays as 1.
+	PlayerHealth       int `json:"player_health"`
+	PlayerDamage       int `json:"player_damage"`
+	MissileDamage      int `json:"missile_damage"`
+	EnemyMissileDamage int `json:"enemy_missile_damage"`
+	// HitFlash is how many seconds a ship flashes for when it's hit, which is
+	// also how long before it can be rammed again. Without it, ships that
+	// survive ramming would take the damage again every tick.
+	HitFlash float64 `json:"hit_flash"`
+	// Enemies are the kinds of enemy ship. Without any, every ship is a
+	// plain one that any hit destroys.
+	Enemies []EnemyClass `json:"enemies"`
+	// Loot is what shot-down enemy ships drop. Without any drops, nothing is.
+	Loot Loot `json:"loot"`
+}
+
+// EnemyClass is a kind of enemy ship. Its speed, fire rate and scale are
+// multiples of the tuning's EnemySpeed, EnemyFireRate and EnemyScale, so
+// difficulties scale every class alike.
+type EnemyClass struct {
+	Name string `json:"name"`
+	// Weight is how often the class turns up, against the other classes'.
+	Weight float64 `json:"weight"`
+	Health int     `json:"health"`
+	// Damage is what the ship does to the player by ramming.
+	Damage int `json:"damage"`
+	// Points is what shooting the ship down scores.
+	Points      int64   `json:"points"`
+	SpeedMul    float64 `json:"speed_mul"`
+	FireRateMul float64 `json:"fire_rate_mul"`
+	ScaleMul    float64 `json:"scale_mul"`
+}
+
+// PowerUp is a kind of pickup.
+type PowerUp string
+
+const (
+	Spread    PowerUp = "spread"
+	RapidFire PowerUp = "rapid_fire"
+	Shield    PowerUp = "shield"
+	ExtraLife PowerUp = "extra_life"
+)
+
+// PowerUps are all the kinds of pickup, and Timed the ones that wear off.
+var (
+	PowerUps = []PowerUp{Spread, RapidFire, Shield, ExtraLife}
+	Timed    = []PowerUp{Spread, RapidFire, Shield}
+)
+
+// Loot is the loot table, and how the pickups on it behave.
+type Loot struct {
+	// Drops are what a ship can drop, each with its own chance. A ship drops
+	// one of them at most.
+	Drops []Drop `json:"drops"`
+	// Lifetime is how many seconds a pickup lasts, flashing for the last
+	// Warning of them. A timed power-up's flashes in its last Warning too.
+	Lifetime float64 `json:"lifetime"`
+	Warning  float64 `json:"warning"`
+	// Drift is how fast the current carries pickups toward the harbor.
+	Drift  float64 `json:"drift"`
+	Radius float64 `json:"radius"`
+	// SpreadAngle is how many degrees spread shot's outer missiles turn
+	// from the middle one, and RapidFireRate how many shots a second rapid
+	// fire fires while fire is held.
+	SpreadAngle   float64 `json:"spread_angle"`
+	RapidFireRate float64 `json:"rapid_fire_rate"`
+}
+
+// Drop is one line of the loot table.
+type Drop struct {
+	Kind PowerUp `json:"kind"`
+	// Chance is how likely a ship is to drop it, from 0 to 1.
+	Chance float64 `json:"chance"`
+	// Duration is how many seconds a timed power-up lasts once picked up.
+	Duration float64 `json:"duration"`
+}
+
+// Duration is how long a power-up of kind lasts.
+func (l Loot) Duration(kind PowerUp) float64 {
+	for _, d := range l.Drops {
+		if d.Kind == kind {
+			return d.Duration
+		}
+	}
+	return 0
+}
+
+// plainEnemy is the only enemy class of tunings that don't list any.
+var plainEnemy = EnemyClass{Name: "plain", Weight: 1, Health: 1, Damage: 1, Points: 1, SpeedMul: 1, FireRateMul: 1, ScaleMul: 1}
+
+func (t Tuning) enemyClasses() []EnemyClass {
+	if len(t.Enemies) == 0 {
+		return []EnemyClass{plainEnemy}
+	}
+	return t.Enemies
+}
+
+// ExtraLivesAt is how many extra lives a score has earned.
+func (t Tuning) ExtraLivesAt(score int64) int {
+	n := 0
+	last := int64(0)
+	for _, at := range t.ExtraLives {
+		if score < at {
+			return n
+		}
+		n++
+		last = at
+	}
+	if t.ExtraLifeEvery > 0 {
+		n += int((score - last) / t.ExtraLifeEvery)
+	}
+	return n
+}
+
+// DefaultTuning is how the game plays out of the box.
+func DefaultTuning() Tuning {
+	return Tuning{
+		Padding:              25,
+		PlayerSpeed:          180,
+		EnemySpeed:           90,
+		MissileSpeed:         210,
+		EnemyFireRate:        0.156,
+		EnemyMissileMul:      1.5,
+		EnemyMissileLifetime: 10,
+		MaxEnemies:           4,
+		PlayerScale:          0.065,
+		EnemyScale:           0.065,
+		MissileScale:         0.035,
+		Lives:                3,
+		ExtraLives:           []int64{10, 25, 50},
+		ExtraLifeEvery:       50,
+		Invulnerable:         2,
+		PlayerHealth:         3,
+		PlayerDamage:         2,
+		MissileDamage:        1,
+		EnemyMissileDamage:   1,
+		HitFlash:             0.12,
+		Enemies: []EnemyClass{
+			{Name: "sloop", Weight: 6, Health: 1, Damage: 1, Points: 1, SpeedMul: 1, FireRateMul: 1, ScaleMul: 1},
+			{Name: "brig", Weight: 3, Health: 3, Damage: 2, Points: 3, SpeedMul: 0.8, FireRateMul: 1.5, ScaleMul: 1.3},
+			{Name: "galleon", Weight: 1, Health: 6, Damage: 3, Points: 6, SpeedMul: 0.6, FireRateMul: 2, ScaleMul: 1.6},
+		},
+		Loot: Loot{
+			Drops: []Drop{
+				{Kind: Spread, Chance: 0.08, Duration: 10},
+				{Kind: RapidFire, Chance: 0.08, Duration: 8},
+				{Kind: Shield, Chance: 0.05, Duration: 6},
+				{Kind: ExtraLife, Chance: 0.02},
+			},
+			Lifetime:      8,
+			Warning:       2.5,
+			Drift:         45,
+			Radius:        14,
+			SpreadAngle:   12,
+			RapidFireRate: 6,
+		},
+	}
+}
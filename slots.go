@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/i18n"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/saves"
+	"github.com/TheKaterTot/pixelTest/ui"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+// slotsScene lists the save slots. Given a game it saves that game into the
+// chosen slot; given nil it loads the chosen slot and plays it.
+type slotsScene struct {
+	app     *app
+	game    *game
+	slots   []string
+	files   map[string]*saves.File
+	errs    map[string]error
+	message string
+	list    *ui.List
+	ui      *ui.UI
+}
+
+func newSlotsScene(a *app, g *game) *slotsScene {
+	s := &slotsScene{app: a, game: g}
+	for _, slot := range saves.Slots {
+		// The autosave belongs to the game; players only load from it.
+		if g != nil && slot == saves.Autosave {
+			continue
+		}
+		s.slots = append(s.slots, slot)
+	}
+
+	title := "slots.load_title"
+	if g != nil {
+		title = "slots.save_title"
+	}
+	u := a.newUI()
+	s.list = u.List(nil, s.pick)
+	u.Add(u.VStack(
+		u.StyledLabel(a.tr(title), titleStyle),
+		ui.NewSpace(0, 16),
+		s.list,
+		ui.NewSpace(0, 16),
+		u.Button(a.tr("menu.back"), func() { a.scenes.pop() }),
+		ui.NewSpace(0, 16),
+		u.Label(func() string { return s.message }),
+	), hud.TopLeft, titleMargin)
+	s.ui = u
+	s.refresh()
+	return s
+}
+
+// refresh reads what's in each slot.
+func (s *slotsScene) refresh() {
+	s.files = map[string]*saves.File{}
+	s.errs = map[string]error{}
+	for _, slot := range s.slots {
+		s.files[slot], s.errs[slot] = saves.Read(slot)
+	}
+	s.list.Items = s.list.Items[:0]
+	for _, slot := range s.slots {
+		s.list.Items = append(s.list.Items, s.label(slot))
+	}
+}
+
+// label describes what's in slot.
+func (s *slotsScene) label(slot string) string {
+	args := i18n.Args{"slot": s.app.t("slot." + slot)}
+	f := s.files[slot]
+	switch {
+	case s.errs[slot] != nil:
+		return s.app.catalog.T("slots.unreadable", args)
+	case f == nil:
+		return s.app.catalog.T("slots.empty", args)
+	}
+	args["score"] = f.State.Score
+	args["time"] = f.SavedAt.Format("2006-01-02 15:04")
+	return s.app.catalog.T("slots.entry", args)
+}
+
+func (s *slotsScene) enter() {}
+
+func (s *slotsScene) exit() {}
+
+func (s *slotsScene) pick(i int) {
+	slot := s.slots[i]
+	if s.game != nil {
+		if err := saves.Write(slot, s.game.world); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			s.message = s.app.catalog.T("slots.save_failed", i18n.Args{"error": err})
+			return
+		}
+		s.message = s.app.catalog.T("slots.saved", i18n.Args{"slot": s.app.t("slot." + slot)})
+		s.refresh()
+		return
+	}
+
+	world, err := saves.Load(slot, s.app.spriteDefs)
+	if err != nil {
+		s.message = s.app.catalog.T("slots.load_failed", i18n.Args{"error": err})
+		return
+	}
+	s.app.scenes.replace(resumePlayingScene(s.app, world))
+}
+
+func (s *slotsScene) update(dt float64) {
+	if s.app.actions.JustPressed(input.Back) {
+		s.app.scenes.pop()
+		return
+	}
+	s.ui.Update(s.app.uiInput(), s.app.world)
+}
+
+func (s *slotsScene) draw(win *pixelgl.Window) {
+	win.Clear(colornames.Mediumaquamarine)
+	s.ui.Draw(win, s.app.world)
+}
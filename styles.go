@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/faiface/pixel"
+	"golang.org/x/image/colornames"
+)
+
+// fontsDir is where extra TrueType and OpenType fonts go in the assets, and
+// langDir where the message catalogs are.
+const (
+	fontsDir = "fonts"
+	langDir  = "lang"
+)
+
+// The text styles the scenes share. The menus keep the look of the 7x13
+// bitmap font they started with; the in-game HUD is outlined so it reads
+// over anything.
+var (
+	titleStyle  = hud.Style{Font: hud.Basic, Size: 39, Color: colornames.Black}
+	menuStyle   = hud.Style{Font: hud.Basic, Size: 26, Color: colornames.Black}
+	bannerStyle = hud.Style{Font: hud.Basic, Size: 52, Color: colornames.White, Align: hud.AlignCenter}
+	hudStyle    = hud.Style{Font: hud.GoBold, Size: 28, Color: colornames.White, Outline: colornames.Black, OutlineWidth: 2}
+	// effectsStyle lists the power-ups running, under the lives.
+	effectsStyle = hud.Style{Font: hud.GoBold, Size: 20, Color: colornames.White, Outline: colornames.Black, OutlineWidth: 2, Align: hud.AlignRight}
+	statusStyle  = hud.Style{Font: hud.Go, Size: 16, Color: colornames.Black}
+	scoresStyle  = hud.Style{Font: hud.Basic, Size: 26, Color: colornames.Black}
+)
+
+// titleMargin places a screen's title, from the top left, where it has always
+// started.
+var titleMargin = pixel.V(100, 235)
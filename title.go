@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/ui"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+type titleScene struct {
+	app *app
+	ui  *ui.UI
+}
+
+func newTitleScene(a *app) *titleScene {
+	u := a.newUI()
+	menu := u.VStack(
+		u.Button(a.tr("menu.start"), func() { a.scenes.replace(newPlayingScene(a)) }),
+		u.Button(a.tr("menu.load"), func() { a.scenes.push(newSlotsScene(a, nil)) }),
+		u.Button(a.tr("menu.settings"), func() { a.scenes.push(newSettingsScene(a)) }),
+		u.Button(a.tr("menu.quit"), func() { a.win.SetClosed(true) }),
+	)
+	menu.Stretch = true
+	u.Add(u.VStack(u.StyledLabel(a.tr("title.story"), titleStyle), ui.NewSpace(0, 16), menu), hud.TopLeft, titleMargin)
+	u.Add(u.StyledLabel(scoresText(a, nil), scoresStyle), hud.BottomRight, pixel.V(100, 40))
+	return &titleScene{app: a, ui: u}
+}
+
+func (s *titleScene) enter() {}
+
+func (s *titleScene) exit() {}
+
+func (s *titleScene) update(dt float64) {
+	s.ui.Update(s.app.uiInput(), s.app.world)
+}
+
+func (s *titleScene) draw(win *pixelgl.Window) {
+	win.Clear(colornames.Mediumaquamarine)
+	s.ui.Draw(win, s.app.world)
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Align is where a stack puts children narrower than itself, across the way
+// it stacks them.
+type Align int
+
+const (
+	Start Align = iota
+	Middle
+	End
+)
+
+// Stack lays its children out one after another, top to bottom or left to
+// right, Spacing apart.
+type Stack struct {
+	box
+	Children []Widget
+	Spacing  float64
+	Align    Align
+	// Stretch widens every child to the stack's full width, or height for a
+	// horizontal stack.
+	Stretch bool
+	across  bool
+}
+
+// VStack stacks children top to bottom.
+func (u *UI) VStack(children ...Widget) *Stack {
+	return &Stack{Children: children, Spacing: u.Theme.Spacing}
+}
+
+// HStack stacks children left to right.
+func (u *UI) HStack(children ...Widget) *Stack {
+	return &Stack{Children: children, Spacing: u.Theme.Spacing, across: true}
+}
+
+func (s *Stack) Size() pixel.Vec {
+	var along, wide float64
+	for i, c := range s.Children {
+		size := c.Size()
+		if s.across {
+			size = pixel.V(size.Y, size.X)
+		}
+		if i > 0 {
+			along += s.Spacing
+		}
+		along += size.Y
+		wide = math.Max(wide, size.X)
+	}
+	if s.across {
+		return pixel.V(along, wide)
+	}
+	return pixel.V(wide, along)
+}
+
+func (s *Stack) Layout(r pixel.Rect) {
+	s.rect = r
+	// Vertical stacks start at the top; horizontal ones at the left.
+	x, y := r.Min.X, r.Max.Y
+	for _, c := range s.Children {
+		size := c.Size()
+		var min pixel.Vec
+		if s.across {
+			if s.Stretch {
+				size.Y = r.H()
+			}
+			min = pixel.V(x, r.Max.Y-s.offset(r.H(), size.Y)-size.Y)
+			x += size.X + s.Spacing
+		} else {
+			if s.Stretch {
+				size.X = r.W()
+			}
+			min = pixel.V(r.Min.X+s.offset(r.W(), size.X), y-size.Y)
+			y -= size.Y + s.Spacing
+		}
+		c.Layout(pixel.Rect{Min: min, Max: min.Add(size)})
+	}
+}
+
+// offset is how far along the cross axis a child of size goes in room, from
+// where Start puts it.
+func (s *Stack) offset(room, size float64) float64 {
+	switch s.Align {
+	case Middle:
+		return (room - size) / 2
+	case End:
+		return room - size
+	}
+	return 0
+}
+
+func (s *Stack) draw(d *drawer) {
+	for _, c := range s.Children {
+		c.draw(d)
+	}
+}
+
+func (s *Stack) focusables() []focusable {
+	all := []focusable{}
+	for _, c := range s.Children {
+		all = append(all, c.focusables()...)
+	}
+	return all
+}
+
+// Space is an empty widget, for gaps.
+type Space struct {
+	box
+	size pixel.Vec
+}
+
+func NewSpace(w, h float64) *Space {
+	return &Space{size: pixel.V(w, h)}
+}
+
+func (s *Space) Size() pixel.Vec { return s.size }
+func (s *Space) draw(d *drawer)  {}
+
+// Panel fills the space behind its child, Padding around it.
+type Panel struct {
+	box
+	Child   Widget
+	Padding float64
+}
+
+func (u *UI) Panel(child Widget) *Panel {
+	return &Panel{Child: child, Padding: u.Theme.Padding}
+}
+
+func (p *Panel) Size() pixel.Vec {
+	return p.Child.Size().Add(pixel.V(2*p.Padding, 2*p.Padding))
+}
+
+func (p *Panel) Layout(r pixel.Rect) {
+	p.rect = r
+	p.Child.Layout(pixel.R(r.Min.X+p.Padding, r.Min.Y+p.Padding, r.Max.X-p.Padding, r.Max.Y-p.Padding))
+}
+
+func (p *Panel) draw(d *drawer) {
+	d.rect(p.rect, d.theme.Panel)
+	p.Child.draw(d)
+}
+
+func (p *Panel) focusables() []focusable {
+	return p.Child.focusables()
+}
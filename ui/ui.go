@@ -0,0 +1,270 @@
+// Package ui is a small retained-mode toolkit for the game's menus: panels,
+// buttons, lists, sliders, toggles and text fields, laid out in stacks and
+// anchored to the screen, with keyboard and mouse focus. Shapes are drawn
+// with imdraw and text with hud labels.
+//
+// A screen builds its widgets once, adds them to a UI, and then each frame
+// calls Update with that frame's Input, and Draw.
+package ui
+
+import (
+	"image/color"
+
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// Text is a widget's text, asked for every frame so it can follow a change
+// of language or a changing value.
+type Text func() string
+
+// Static is Text that never changes.
+func Static(s string) Text {
+	return func() string { return s }
+}
+
+// Input is what the player did this frame, in terms the widgets understand.
+// Mouse is in the same coordinates as the area the UI is laid out in.
+type Input struct {
+	Up, Down, Left, Right bool
+	Confirm               bool
+	Mouse                 pixel.Vec
+	// Click is set the frame the mouse button goes down, Held while it stays
+	// down.
+	Click, Held bool
+	// Typed is the text typed this frame, and Erase a press of backspace.
+	Typed string
+	Erase bool
+}
+
+// Theme is how widgets look.
+type Theme struct {
+	Text hud.Style
+	// Panel fills panels, Focus the widget with focus, and Accent the filled
+	// parts of toggles and sliders. Idle fills a button without focus, and
+	// may be nil.
+	Panel, Focus, Idle, Accent, Track color.Color
+	Padding, Spacing                  float64
+	// SliderWidth is how long a slider's track is.
+	SliderWidth float64
+}
+
+// Widget is anything that can be laid out and drawn.
+type Widget interface {
+	// Size is the space the widget wants.
+	Size() pixel.Vec
+	// Layout puts the widget at r.
+	Layout(r pixel.Rect)
+	// Bounds is where the widget was last laid out.
+	Bounds() pixel.Rect
+	draw(d *drawer)
+	// focusables lists the widgets inside that can take focus, in order.
+	focusables() []focusable
+}
+
+// focusable is a widget the player can move to and work.
+type focusable interface {
+	Widget
+	// handle reacts to in while the widget has focus, and reports whether it
+	// used the direction keys, so that those it didn't can move focus.
+	handle(in *Input) bool
+	// click reacts to the mouse going down on the widget, or being dragged
+	// after going down on it.
+	click(in *Input)
+	setFocused(focused bool)
+}
+
+// box is the part of every widget that remembers where it was laid out.
+type box struct {
+	rect pixel.Rect
+}
+
+func (b *box) Layout(r pixel.Rect)     { b.rect = r }
+func (b *box) Bounds() pixel.Rect      { return b.rect }
+func (b *box) focusables() []focusable { return nil }
+
+// drawer collects a frame's shapes and text. Text goes over every shape, so
+// nothing drawn later in the frame can hide it.
+type drawer struct {
+	theme *Theme
+	imd   *imdraw.IMDraw
+	text  []placedLabel
+}
+
+type placedLabel struct {
+	label *hud.Label
+	area  pixel.Rect
+}
+
+func (d *drawer) rect(r pixel.Rect, c color.Color) {
+	if c == nil {
+		return
+	}
+	d.imd.Color = c
+	d.imd.Push(r.Min, r.Max)
+	d.imd.Rectangle(0)
+}
+
+func (d *drawer) outline(r pixel.Rect, c color.Color, width float64) {
+	if c == nil {
+		return
+	}
+	d.imd.Color = c
+	d.imd.Push(r.Min, r.Max)
+	d.imd.Rectangle(width)
+}
+
+func (d *drawer) label(l *hud.Label, area pixel.Rect) {
+	d.text = append(d.text, placedLabel{label: l, area: area})
+}
+
+type root struct {
+	widget Widget
+	anchor hud.Anchor
+	margin pixel.Vec
+}
+
+// UI is one screen's widgets and which of them has focus.
+type UI struct {
+	Theme Theme
+	fonts *hud.Fonts
+	roots []root
+	focus focusable
+	// dragging is the widget the mouse went down on, while it stays down.
+	dragging  focusable
+	lastMouse pixel.Vec
+	imd       *imdraw.IMDraw
+}
+
+func New(fonts *hud.Fonts, theme Theme) *UI {
+	return &UI{Theme: theme, fonts: fonts, imd: imdraw.New(nil)}
+}
+
+// Add puts w on the screen at anchor, margin in from the edges. Focus starts
+// on the first widget that can take it.
+func (u *UI) Add(w Widget, anchor hud.Anchor, margin pixel.Vec) {
+	u.roots = append(u.roots, root{widget: w, anchor: anchor, margin: margin})
+	if u.focus == nil {
+		if all := w.focusables(); len(all) > 0 {
+			u.setFocus(all[0])
+		}
+	}
+}
+
+// Focus gives w focus, if it can take it.
+func (u *UI) Focus(w Widget) {
+	if f, ok := w.(focusable); ok {
+		u.setFocus(f)
+	}
+}
+
+// Focused is the widget with focus, or nil.
+func (u *UI) Focused() Widget {
+	if u.focus == nil {
+		return nil
+	}
+	return u.focus
+}
+
+func (u *UI) setFocus(f focusable) {
+	if u.focus != nil {
+		u.focus.setFocused(false)
+	}
+	u.focus = f
+	if f != nil {
+		f.setFocused(true)
+	}
+}
+
+func (u *UI) focusables() []focusable {
+	all := []focusable{}
+	for _, r := range u.roots {
+		all = append(all, r.widget.focusables()...)
+	}
+	return all
+}
+
+func (u *UI) layout(area pixel.Rect) {
+	for _, r := range u.roots {
+		r.widget.Layout(r.anchor.Place(r.widget.Size(), area, r.margin))
+	}
+}
+
+// Typing reports whether a text field has focus, so screens can leave keys
+// that type alone.
+func (u *UI) Typing() bool {
+	_, typing := u.focus.(*TextField)
+	return typing
+}
+
+// Update lays the widgets out in area and hands them the frame's input.
+func (u *UI) Update(in Input, area pixel.Rect) {
+	u.layout(area)
+
+	all := u.focusables()
+	// Moving the mouse over a widget focuses it; the keyboard takes over again
+	// once the mouse stops.
+	var hover focusable
+	for _, f := range all {
+		if f.Bounds().Contains(in.Mouse) {
+			hover = f
+		}
+	}
+	if hover != nil && in.Mouse != u.lastMouse {
+		u.setFocus(hover)
+	}
+	u.lastMouse = in.Mouse
+
+	if in.Click && hover != nil {
+		u.dragging = hover
+	}
+	if !in.Held && !in.Click {
+		u.dragging = nil
+	}
+	if u.dragging != nil {
+		u.dragging.click(&in)
+		return
+	}
+
+	if u.focus == nil {
+		return
+	}
+	if u.Typing() && in.Typed != "" {
+		// Letters can be bound to moving too.
+		in.Up, in.Down, in.Left, in.Right = false, false, false, false
+	}
+	if u.focus.handle(&in) {
+		return
+	}
+	i := indexOf(all, u.focus)
+	switch {
+	case in.Up && len(all) > 0:
+		u.setFocus(all[(i+len(all)-1)%len(all)])
+	case in.Down && len(all) > 0:
+		u.setFocus(all[(i+1)%len(all)])
+	}
+}
+
+func indexOf(all []focusable, f focusable) int {
+	for i, it := range all {
+		if it == f {
+			return i
+		}
+	}
+	return 0
+}
+
+// Draw lays the widgets out in area and draws them.
+func (u *UI) Draw(t pixel.Target, area pixel.Rect) {
+	u.layout(area)
+	u.imd.Clear()
+	d := &drawer{theme: &u.Theme, imd: u.imd}
+	for _, r := range u.roots {
+		r.widget.draw(d)
+	}
+	u.imd.Draw(t)
+	for _, l := range d.text {
+		l.label.Draw(t, l.area)
+	}
+}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/faiface/pixel"
+)
+
+var (
+	testArea  = pixel.R(0, 0, 800, 600)
+	testTheme = Theme{Text: hud.Style{Font: hud.Go, Size: 16}, Padding: 4, Spacing: 4, SliderWidth: 100}
+	// away keeps the mouse off every widget, so only the keys move focus.
+	away = pixel.V(-100, -100)
+)
+
+func newTestUI() *UI {
+	return New(hud.NewFonts(), testTheme)
+}
+
+// press hands u a frame of in, with the mouse away.
+func press(u *UI, in Input) {
+	in.Mouse = away
+	u.Update(in, testArea)
+}
+
+func TestFocusStartsOnFirstFocusable(t *testing.T) {
+	u := newTestUI()
+	first := u.Button(Static("first"), nil)
+	u.Add(u.VStack(u.Label(Static("title")), first, u.Button(Static("second"), nil)), hud.TopLeft, pixel.ZV)
+	if u.Focused() != first {
+		t.Errorf("focus = %v, want the first button", u.Focused())
+	}
+}
+
+func TestUpDownMoveFocusAndWrap(t *testing.T) {
+	u := newTestUI()
+	a, b, c := u.Button(Static("a"), nil), u.Button(Static("b"), nil), u.Button(Static("c"), nil)
+	u.Add(u.VStack(a, u.Label(Static("not focusable")), b, c), hud.TopLeft, pixel.ZV)
+
+	for _, step := range []struct {
+		in   Input
+		want Widget
+	}{
+		{Input{Down: true}, b},
+		{Input{Down: true}, c},
+		{Input{Down: true}, a},
+		{Input{Up: true}, c},
+		{Input{Up: true}, b},
+	} {
+		press(u, step.in)
+		if u.Focused() != step.want {
+			t.Fatalf("after %+v focus is on %q", step.in, u.Focused().(*Button).Text())
+		}
+	}
+}
+
+func TestFocusCrossesRoots(t *testing.T) {
+	u := newTestUI()
+	a, b := u.Button(Static("a"), nil), u.Button(Static("b"), nil)
+	u.Add(a, hud.TopLeft, pixel.ZV)
+	u.Add(b, hud.BottomRight, pixel.ZV)
+	press(u, Input{Down: true})
+	if u.Focused() != b {
+		t.Error("down didn't move focus to the second root")
+	}
+}
+
+func TestListKeepsUpDownUntilItsEnds(t *testing.T) {
+	u := newTestUI()
+	before, after := u.Button(Static("before"), nil), u.Button(Static("after"), nil)
+	list := u.List([]string{"one", "two", "three"}, nil)
+	u.Add(u.VStack(before, list, after), hud.TopLeft, pixel.ZV)
+
+	press(u, Input{Down: true})
+	if u.Focused() != list {
+		t.Fatal("down didn't move focus onto the list")
+	}
+	for want := 1; want <= 2; want++ {
+		press(u, Input{Down: true})
+		if u.Focused() != list || list.Selected != want {
+			t.Fatalf("down moved to row %d with focus on list %v, want row %d", list.Selected, u.Focused() == list, want)
+		}
+	}
+	press(u, Input{Down: true})
+	if u.Focused() != after {
+		t.Error("down on the last row didn't move focus off the list")
+	}
+
+	u.Focus(list)
+	list.Selected = 0
+	press(u, Input{Up: true})
+	if u.Focused() != before {
+		t.Error("up on the first row didn't move focus off the list")
+	}
+}
+
+func TestListPicksSelectedRow(t *testing.T) {
+	u := newTestUI()
+	picked := -1
+	list := u.List([]string{"one", "two"}, func(i int) { picked = i })
+	u.Add(list, hud.TopLeft, pixel.ZV)
+	press(u, Input{Down: true})
+	press(u, Input{Confirm: true})
+	if picked != 1 {
+		t.Errorf("picked %d, want 1", picked)
+	}
+}
+
+func TestToggleFlipsAndKeepsFocus(t *testing.T) {
+	u := newTestUI()
+	changes := []bool{}
+	toggle := u.Toggle(Static("vsync"), false, func(on bool) { changes = append(changes, on) })
+	u.Add(u.VStack(toggle, u.Button(Static("next"), nil)), hud.TopLeft, pixel.ZV)
+
+	press(u, Input{Confirm: true})
+	press(u, Input{Right: true})
+	if toggle.On || len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("on = %v after changes %v, want off after [true false]", toggle.On, changes)
+	}
+	if u.Focused() != toggle {
+		t.Error("right moved focus off the toggle")
+	}
+}
+
+func TestSliderStepsAndClamps(t *testing.T) {
+	u := newTestUI()
+	slider := u.Slider(Static("cap"), 0, 2, 1, 1, nil)
+	u.Add(slider, hud.TopLeft, pixel.ZV)
+
+	for _, step := range []struct {
+		in   Input
+		want float64
+	}{
+		{Input{Right: true}, 2},
+		{Input{Right: true}, 2},
+		{Input{Left: true}, 1},
+		{Input{Left: true}, 0},
+		{Input{Left: true}, 0},
+	} {
+		press(u, step.in)
+		if slider.Value != step.want {
+			t.Fatalf("after %+v value = %v, want %v", step.in, slider.Value, step.want)
+		}
+	}
+}
+
+func TestHoverTakesFocus(t *testing.T) {
+	u := newTestUI()
+	a, b := u.Button(Static("a"), nil), u.Button(Static("b"), nil)
+	u.Add(u.HStack(a, b), hud.TopLeft, pixel.ZV)
+	press(u, Input{})
+
+	u.Update(Input{Mouse: b.Bounds().Center()}, testArea)
+	if u.Focused() != b {
+		t.Fatal("hovering didn't focus the button under the mouse")
+	}
+	// A still mouse leaves the keyboard in charge.
+	u.Update(Input{Mouse: b.Bounds().Center(), Up: true}, testArea)
+	if u.Focused() != a {
+		t.Error("the keyboard couldn't move focus from under a still mouse")
+	}
+}
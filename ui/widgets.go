@@ -0,0 +1,434 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/faiface/pixel"
+)
+
+// newLabel makes a label that sits in whatever area it's drawn in the way its
+// style aligns it.
+func (u *UI) newLabel(style hud.Style) *hud.Label {
+	anchor := hud.TopLeft
+	switch style.Align {
+	case hud.AlignCenter:
+		anchor = hud.Top
+	case hud.AlignRight:
+		anchor = hud.TopRight
+	}
+	return u.fonts.Label(style, anchor, pixel.ZV)
+}
+
+// Label is text that can't be focused.
+type Label struct {
+	box
+	Text  Text
+	label *hud.Label
+}
+
+// Label makes a label in the theme's text style.
+func (u *UI) Label(text Text) *Label {
+	return u.StyledLabel(text, u.Theme.Text)
+}
+
+func (u *UI) StyledLabel(text Text, style hud.Style) *Label {
+	return &Label{Text: text, label: u.newLabel(style)}
+}
+
+func (l *Label) Size() pixel.Vec {
+	l.label.SetText(l.Text())
+	return l.label.Size()
+}
+
+func (l *Label) draw(d *drawer) {
+	d.label(l.label, l.rect)
+}
+
+// focus is the part of every focusable widget that remembers whether it has
+// focus.
+type focus struct {
+	focused bool
+}
+
+func (f *focus) setFocused(focused bool) { f.focused = focused }
+
+// Button does something when it's confirmed or clicked.
+type Button struct {
+	box
+	focus
+	Text    Text
+	OnPress func()
+	label   *hud.Label
+	padding float64
+}
+
+func (u *UI) Button(text Text, onPress func()) *Button {
+	style := u.Theme.Text
+	style.Align = hud.AlignCenter
+	return &Button{Text: text, OnPress: onPress, label: u.fonts.Label(style, hud.Center, pixel.ZV), padding: u.Theme.Padding}
+}
+
+func (b *Button) Size() pixel.Vec {
+	b.label.SetText(b.Text())
+	return b.label.Size().Add(pixel.V(4*b.padding, 2*b.padding))
+}
+
+func (b *Button) focusables() []focusable { return []focusable{b} }
+
+func (b *Button) press() {
+	if b.OnPress != nil {
+		b.OnPress()
+	}
+}
+
+func (b *Button) handle(in *Input) bool {
+	if in.Confirm {
+		b.press()
+	}
+	return false
+}
+
+func (b *Button) click(in *Input) {
+	if in.Click {
+		b.press()
+	}
+}
+
+func (b *Button) draw(d *drawer) {
+	if b.focused {
+		d.rect(b.rect, d.theme.Focus)
+	} else {
+		d.rect(b.rect, d.theme.Idle)
+	}
+	d.label(b.label, b.rect)
+}
+
+// Toggle is a setting that's on or off. Confirm, left, right and clicks flip
+// it.
+type Toggle struct {
+	box
+	focus
+	Text     Text
+	On       bool
+	OnChange func(on bool)
+	label    *hud.Label
+	spacing  float64
+}
+
+func (u *UI) Toggle(text Text, on bool, onChange func(on bool)) *Toggle {
+	return &Toggle{Text: text, On: on, OnChange: onChange, label: u.newLabel(u.Theme.Text), spacing: u.Theme.Spacing}
+}
+
+func (t *Toggle) Size() pixel.Vec {
+	t.label.SetText(t.Text())
+	size := t.label.Size()
+	return pixel.V(size.X+t.spacing+size.Y, size.Y)
+}
+
+func (t *Toggle) focusables() []focusable { return []focusable{t} }
+
+func (t *Toggle) flip() {
+	t.On = !t.On
+	if t.OnChange != nil {
+		t.OnChange(t.On)
+	}
+}
+
+func (t *Toggle) handle(in *Input) bool {
+	if in.Confirm || in.Left || in.Right {
+		t.flip()
+	}
+	return in.Left || in.Right
+}
+
+func (t *Toggle) click(in *Input) {
+	if in.Click {
+		t.flip()
+	}
+}
+
+// check is the box that shows whether the toggle is on, at the right.
+func (t *Toggle) check() pixel.Rect {
+	side := t.rect.H()
+	return pixel.R(t.rect.Max.X-side, t.rect.Min.Y, t.rect.Max.X, t.rect.Max.Y)
+}
+
+func (t *Toggle) draw(d *drawer) {
+	if t.focused {
+		d.rect(t.rect, d.theme.Focus)
+	}
+	check := t.check()
+	d.outline(check, d.theme.Accent, 2)
+	if t.On {
+		inset := check.H() / 4
+		d.rect(pixel.R(check.Min.X+inset, check.Min.Y+inset, check.Max.X-inset, check.Max.Y-inset), d.theme.Accent)
+	}
+	d.label(t.label, t.rect)
+}
+
+// Slider picks a number between Min and Max, Step at a time from the keyboard
+// or anywhere along its track with the mouse.
+type Slider struct {
+	box
+	focus
+	Text           Text
+	Min, Max, Step float64
+	Value          float64
+	OnChange       func(v float64)
+	label          *hud.Label
+	width, spacing float64
+}
+
+func (u *UI) Slider(text Text, min, max, step, value float64, onChange func(v float64)) *Slider {
+	return &Slider{
+		Text: text, Min: min, Max: max, Step: step, Value: value, OnChange: onChange,
+		label: u.newLabel(u.Theme.Text), width: u.Theme.SliderWidth, spacing: u.Theme.Spacing,
+	}
+}
+
+func (s *Slider) Size() pixel.Vec {
+	s.label.SetText(s.Text())
+	size := s.label.Size()
+	return pixel.V(size.X+s.spacing+s.width, size.Y)
+}
+
+func (s *Slider) focusables() []focusable { return []focusable{s} }
+
+func (s *Slider) set(v float64) {
+	v = math.Max(s.Min, math.Min(s.Max, v))
+	if s.Step > 0 {
+		v = s.Min + math.Round((v-s.Min)/s.Step)*s.Step
+	}
+	if v == s.Value {
+		return
+	}
+	s.Value = v
+	if s.OnChange != nil {
+		s.OnChange(v)
+	}
+}
+
+func (s *Slider) handle(in *Input) bool {
+	if in.Left {
+		s.set(s.Value - s.Step)
+	}
+	if in.Right {
+		s.set(s.Value + s.Step)
+	}
+	return in.Left || in.Right
+}
+
+// track is the line the knob slides along, at the right.
+func (s *Slider) track() pixel.Rect {
+	mid := s.rect.Center().Y
+	return pixel.R(s.rect.Max.X-s.width, mid-2, s.rect.Max.X, mid+2)
+}
+
+func (s *Slider) click(in *Input) {
+	track := s.track()
+	s.set(s.Min + (in.Mouse.X-track.Min.X)/track.W()*(s.Max-s.Min))
+}
+
+func (s *Slider) draw(d *drawer) {
+	if s.focused {
+		d.rect(s.rect, d.theme.Focus)
+	}
+	track := s.track()
+	d.rect(track, d.theme.Track)
+	at := 0.0
+	if s.Max > s.Min {
+		at = (s.Value - s.Min) / (s.Max - s.Min)
+	}
+	x := track.Min.X + track.W()*at
+	d.rect(pixel.R(track.Min.X, track.Min.Y, x, track.Max.Y), d.theme.Accent)
+	half := s.rect.H() / 4
+	d.rect(pixel.R(x-half/2, s.rect.Center().Y-half, x+half/2, s.rect.Center().Y+half), d.theme.Accent)
+	d.label(s.label, s.rect)
+}
+
+// List is rows to pick one of, Rows at a time, scrolling to keep the selected
+// row in view. Up and down move through it before moving focus off it.
+type List struct {
+	box
+	focus
+	Items    []string
+	Selected int
+	// Rows is how many rows show at once; 0 shows them all.
+	Rows   int
+	OnPick func(i int)
+	top    int
+	labels []*hud.Label
+	u      *UI
+}
+
+func (u *UI) List(items []string, onPick func(i int)) *List {
+	return &List{Items: items, OnPick: onPick, u: u}
+}
+
+func (l *List) visible() int {
+	if l.Rows > 0 && l.Rows < len(l.Items) {
+		return l.Rows
+	}
+	return len(l.Items)
+}
+
+// rows sets each visible row's label, and returns the height of one.
+func (l *List) rows() float64 {
+	for len(l.labels) < l.visible() {
+		l.labels = append(l.labels, l.u.newLabel(l.u.Theme.Text))
+	}
+	if l.Selected < l.top {
+		l.top = l.Selected
+	}
+	if l.Selected >= l.top+l.visible() {
+		l.top = l.Selected - l.visible() + 1
+	}
+	height := 0.0
+	for i := 0; i < l.visible(); i++ {
+		l.labels[i].SetText(l.Items[l.top+i])
+		height = math.Max(height, l.labels[i].Size().Y)
+	}
+	return height
+}
+
+func (l *List) Size() pixel.Vec {
+	height := l.rows()
+	width := 0.0
+	for _, label := range l.labels[:l.visible()] {
+		width = math.Max(width, label.Size().X)
+	}
+	pad := l.u.Theme.Padding
+	return pixel.V(width+2*pad, float64(l.visible())*height+2*pad)
+}
+
+func (l *List) focusables() []focusable { return []focusable{l} }
+
+func (l *List) pick() {
+	if l.OnPick != nil && l.Selected < len(l.Items) {
+		l.OnPick(l.Selected)
+	}
+}
+
+func (l *List) handle(in *Input) bool {
+	switch {
+	case in.Up && l.Selected > 0:
+		l.Selected--
+		return true
+	case in.Down && l.Selected < len(l.Items)-1:
+		l.Selected++
+		return true
+	case in.Confirm:
+		l.pick()
+	}
+	return false
+}
+
+// row is where the i'th visible row is.
+func (l *List) row(i int) pixel.Rect {
+	pad := l.u.Theme.Padding
+	h := (l.rect.H() - 2*pad) / math.Max(1, float64(l.visible()))
+	top := l.rect.Max.Y - pad - float64(i)*h
+	return pixel.R(l.rect.Min.X, top-h, l.rect.Max.X, top)
+}
+
+func (l *List) click(in *Input) {
+	for i := 0; i < l.visible(); i++ {
+		if l.row(i).Contains(in.Mouse) {
+			l.Selected = l.top + i
+			if in.Click {
+				l.pick()
+			}
+			return
+		}
+	}
+}
+
+func (l *List) draw(d *drawer) {
+	d.rect(l.rect, d.theme.Panel)
+	pad := d.theme.Padding
+	for i := 0; i < l.visible(); i++ {
+		r := l.row(i)
+		if l.top+i == l.Selected {
+			c := d.theme.Idle
+			if l.focused {
+				c = d.theme.Focus
+			}
+			d.rect(r, c)
+		}
+		d.label(l.labels[i], pixel.R(r.Min.X+pad, r.Min.Y, r.Max.X-pad, r.Max.Y))
+	}
+}
+
+// TextField takes typed text, up to Max runes, and hands it to OnSubmit when
+// it's confirmed.
+type TextField struct {
+	box
+	focus
+	Value string
+	Max   int
+	// Filter, when set, has the final say on what typing leaves in Value.
+	Filter   func(s string) string
+	OnSubmit func(s string)
+	label    *hud.Label
+	measure  *hud.Label
+	u        *UI
+}
+
+func (u *UI) TextField(max int, onSubmit func(s string)) *TextField {
+	return &TextField{Max: max, OnSubmit: onSubmit, label: u.newLabel(u.Theme.Text), measure: u.newLabel(u.Theme.Text), u: u}
+}
+
+func (f *TextField) text() string {
+	if f.focused && utf8.RuneCountInString(f.Value) < f.Max {
+		// The blank is where the next letter goes.
+		return f.Value + "_"
+	}
+	return f.Value
+}
+
+func (f *TextField) Size() pixel.Vec {
+	// Wide enough for Max of the widest letter, and the caret.
+	f.measure.SetText(strings.Repeat("W", f.Max+1))
+	pad := f.u.Theme.Padding
+	return f.measure.Size().Add(pixel.V(2*pad, 2*pad))
+}
+
+func (f *TextField) focusables() []focusable { return []focusable{f} }
+
+func (f *TextField) handle(in *Input) bool {
+	if in.Typed != "" {
+		f.u.fonts.AddRunes([]rune(in.Typed))
+		v := f.Value + in.Typed
+		if f.Filter != nil {
+			v = f.Filter(v)
+		}
+		for utf8.RuneCountInString(v) > f.Max {
+			_, size := utf8.DecodeLastRuneInString(v)
+			v = v[:len(v)-size]
+		}
+		f.Value = v
+	}
+	if in.Erase && f.Value != "" {
+		_, size := utf8.DecodeLastRuneInString(f.Value)
+		f.Value = f.Value[:len(f.Value)-size]
+	}
+	if in.Confirm && f.OnSubmit != nil {
+		f.OnSubmit(f.Value)
+	}
+	return in.Left || in.Right
+}
+
+func (f *TextField) click(in *Input) {}
+
+func (f *TextField) draw(d *drawer) {
+	d.rect(f.rect, d.theme.Track)
+	if f.focused {
+		d.outline(f.rect, d.theme.Accent, 2)
+	}
+	f.label.SetText(f.text())
+	pad := d.theme.Padding
+	d.label(f.label, pixel.R(f.rect.Min.X+pad, f.rect.Min.Y+pad, f.rect.Max.X-pad, f.rect.Max.Y-pad))
+}
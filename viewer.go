@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/TheKaterTot/pixelTest/hud"
+	"github.com/TheKaterTot/pixelTest/i18n"
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/replay"
+	"github.com/TheKaterTot/pixelTest/sim"
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4, 8}
+
+// replaySkip is how far, in ticks, a left or right press jumps.
+const replaySkip = 5 * 60
+
+// barHeight is the timeline's height, and statusGap how far above its
+// bottom the status line starts.
+const (
+	barHeight = 16
+	statusGap = barHeight + 8
+)
+
+// replayScene plays a recording with pause, speed control and a timeline the
+// player can click or drag to seek.
+type replayScene struct {
+	app       *app
+	timeline  *replay.Timeline
+	game      *game
+	speed     int
+	paused    bool
+	scrubbing bool
+	acc       float64
+	status    *hud.Label
+}
+
+func newReplayScene(a *app, r *replay.Replay) *replayScene {
+	t := replay.NewTimeline(r, a.spriteDefs)
+	return &replayScene{
+		app:      a,
+		timeline: t,
+		game:     newGameView(t.Game, a.fonts, a.sprites),
+		status:   a.fonts.Label(statusStyle, hud.BottomLeft, pixel.V(hudMargin, hudMargin+statusGap)),
+		speed:    2,
+	}
+}
+
+func (s *replayScene) enter() {}
+
+func (s *replayScene) exit() {}
+
+func (s *replayScene) seek(tick int) {
+	s.timeline.Seek(tick)
+	s.game.setWorld(s.timeline.Game)
+	s.acc = 0
+}
+
+func (s *replayScene) bar() pixel.Rect {
+	b := s.app.world
+	return pixel.R(b.Min.X+hudMargin, b.Min.Y+hudMargin, b.Max.X-hudMargin, b.Min.Y+hudMargin+barHeight)
+}
+
+func (s *replayScene) update(dt float64) {
+	actions, win := s.app.actions, s.app.win
+
+	if actions.JustPressed(input.Back) || actions.JustPressed(input.Quit) {
+		win.SetClosed(true)
+		return
+	}
+	if actions.JustPressed(input.Pause) {
+		s.paused = !s.paused
+	}
+	if actions.JustPressed(input.MoveUp) && s.speed < len(replaySpeeds)-1 {
+		s.speed++
+	}
+	if actions.JustPressed(input.MoveDown) && s.speed > 0 {
+		s.speed--
+	}
+	if actions.JustPressed(input.MoveLeft) {
+		s.seek(s.timeline.Tick - replaySkip)
+	}
+	if actions.JustPressed(input.MoveRight) {
+		s.seek(s.timeline.Tick + replaySkip)
+	}
+
+	bar := s.bar()
+	mouse := s.app.camera.unproject(win.MousePosition())
+	if win.JustPressed(pixelgl.MouseButtonLeft) && bar.Contains(mouse) {
+		s.scrubbing = true
+	}
+	if !win.Pressed(pixelgl.MouseButtonLeft) {
+		s.scrubbing = false
+	}
+	if s.scrubbing {
+		at := (mouse.X - bar.Min.X) / bar.W()
+		s.seek(int(math.Round(at * float64(s.timeline.Len()))))
+		return
+	}
+
+	if s.paused {
+		return
+	}
+	speed := replaySpeeds[s.speed]
+	s.acc += math.Min(dt, maxFrame) * speed
+	for s.acc >= sim.Tick {
+		if !s.timeline.Step() {
+			s.paused = true
+			s.acc = 0
+			break
+		}
+		s.acc -= sim.Tick
+	}
+}
+
+func formatTicks(ticks int) string {
+	seconds := int(float64(ticks) * sim.Tick)
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func (s *replayScene) draw(win *pixelgl.Window) {
+	s.game.draw(win, s.acc/sim.Tick)
+	s.game.displayHUD(win, s.app.world, s.app.catalog)
+
+	bar := s.bar()
+	done := float64(s.timeline.Tick) / math.Max(1, float64(s.timeline.Len()))
+	imd := imdraw.New(nil)
+	imd.Color = colornames.Black
+	imd.Push(bar.Min, bar.Max)
+	imd.Rectangle(0)
+	imd.Color = colornames.Gold
+	imd.Push(bar.Min, pixel.V(bar.Min.X+bar.W()*done, bar.Max.Y))
+	imd.Rectangle(0)
+	imd.Draw(win)
+
+	b := &strings.Builder{}
+	fmt.Fprint(b, s.app.catalog.T("replay.status", i18n.Args{
+		"time":   formatTicks(s.timeline.Tick),
+		"length": formatTicks(s.timeline.Len()),
+		"speed":  replaySpeeds[s.speed],
+	}))
+	if s.paused {
+		fmt.Fprint(b, "  "+s.app.t("replay.paused"))
+	}
+	if s.timeline.Diverged {
+		fmt.Fprint(b, "  "+s.app.t("replay.diverged"))
+	}
+	s.status.SetText(b.String())
+	s.status.Draw(win, s.app.world)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/TheKaterTot/pixelTest/input"
+	"github.com/TheKaterTot/pixelTest/ui"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+// uiTheme is how the menus built from widgets look: black text, with a gold
+// bar behind whatever has focus. Colors are alpha-premultiplied.
+var uiTheme = ui.Theme{
+	Text:        menuStyle,
+	Panel:       color.RGBA{R: 0xd0, G: 0xd0, B: 0xd0, A: 0xd0},
+	Focus:       colornames.Gold,
+	Accent:      colornames.Darkslategray,
+	Track:       color.RGBA{A: 0x40},
+	Padding:     8,
+	Spacing:     8,
+	SliderWidth: 200,
+}
+
+func (a *app) newUI() *ui.UI {
+	return ui.New(a.fonts, uiTheme)
+}
+
+// tr is the active catalog's message for key, looked up each time it's
+// drawn.
+func (a *app) tr(key string) ui.Text {
+	return func() string { return a.t(key) }
+}
+
+// uiInput is this frame's input for widgets, with the mouse in world
+// coordinates.
+func (a *app) uiInput() ui.Input {
+	actions, win := a.actions, a.win
+	return ui.Input{
+		Up:      actions.JustPressed(input.MoveUp),
+		Down:    actions.JustPressed(input.MoveDown),
+		Left:    actions.JustPressed(input.MoveLeft),
+		Right:   actions.JustPressed(input.MoveRight),
+		Confirm: actions.JustPressed(input.Confirm),
+		Mouse:   a.camera.unproject(win.MousePosition()),
+		Click:   win.JustPressed(pixelgl.MouseButtonLeft),
+		Held:    win.Pressed(pixelgl.MouseButtonLeft),
+		Typed:   win.Typed(),
+		Erase:   win.JustPressed(pixelgl.KeyBackspace) || win.Repeated(pixelgl.KeyBackspace),
+	}
+}